@@ -0,0 +1,60 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxChecklistText caps the length of a checklist entry, in characters.
+const MaxChecklistText = 500
+
+// ChecklistEntry is one line of an item's checklist. Entries are ordered by
+// position like the items of a list.
+type ChecklistEntry struct {
+	Id        int       `json:"id" db:"id"`
+	Text      string    `json:"text" db:"text" binding:"required"`
+	Checked   bool      `json:"checked" db:"checked"`
+	Position  string    `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (e ChecklistEntry) Validate() error {
+	return validateChecklistText(e.Text)
+}
+
+type UpdateChecklistEntryInput struct {
+	Text    *string `json:"text"`
+	Checked *bool   `json:"checked"`
+}
+
+func (inp UpdateChecklistEntryInput) Validate() error {
+	if inp.Text == nil && inp.Checked == nil {
+		return fmt.Errorf(validationError, "update checklist entry input")
+	}
+
+	if inp.Text != nil {
+		return validateChecklistText(*inp.Text)
+	}
+
+	return nil
+}
+
+func validateChecklistText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("checklist entry text must not be empty")
+	}
+
+	if len([]rune(text)) > MaxChecklistText {
+		return fmt.Errorf("checklist entry text must be at most %d characters", MaxChecklistText)
+	}
+
+	return nil
+}
+
+// ChecklistProgress counts the checked entries of an item's checklist.
+type ChecklistProgress struct {
+	Done  int `json:"done" db:"checklist_done"`
+	Total int `json:"total" db:"checklist_total"`
+}
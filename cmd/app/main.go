@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	_ "time/tzdata" // due date views take IANA time zones from clients
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/handler"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/Liopun/notes-app/pkg/service"
+	"github.com/Liopun/notes-app/schema"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	migrateOnStart := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-migrate]\n       %s migrate up|down [N]|to VERSION|force VERSION|status\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logrus.SetFormatter(new(logrus.JSONFormatter))
+
+	if err := initConfig(); err != nil {
+		logrus.Fatalf("error initializing configs: %s", err.Error())
+	}
+
+	db, err := repository.NewPostgresDB(repository.Config{
+		Host:     viper.GetString("db.host"),
+		Port:     viper.GetString("db.port"),
+		Username: viper.GetString("db.username"),
+		Password: viper.GetString("db.password"),
+		DName:    viper.GetString("db.dbname"),
+		SSLMode:  viper.GetString("db.sslmode"),
+	})
+	if err != nil {
+		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(db, flag.Args()[1:]); err != nil {
+			logrus.Fatalf("migrate: %s", err.Error())
+		}
+		return
+	}
+
+	if *migrateOnStart {
+		if err := runMigrate(db, []string{"up"}); err != nil {
+			logrus.Fatalf("failed to migrate db: %s", err.Error())
+		}
+	}
+
+	keys, err := loadKeySet()
+	if err != nil {
+		logrus.Fatalf("failed to load signing keys: %s", err.Error())
+	}
+
+	repos := repository.NewRepository(db)
+	services := service.NewService(service.Deps{
+		Repos:           repos,
+		PasswordSalt:    viper.GetString("auth.passwordSalt"),
+		Keys:            keys,
+		TokenTTL:        viper.GetDuration("auth.tokenTTL"),
+		RefreshTokenTTL: viper.GetDuration("auth.refreshTokenTTL"),
+
+		InvitationTTL:           viper.GetDuration("invitations.ttl"),
+		InvitationSweepInterval: viper.GetDuration("invitations.sweepInterval"),
+
+		TrashRetention:     viper.GetDuration("trash.retention"),
+		TrashPurgeInterval: viper.GetDuration("trash.purgeInterval"),
+
+		Notifiers: newNotifiers(repos),
+		ReminderConfig: service.ReminderConfig{
+			BatchSize:    viper.GetInt("reminders.batchSize"),
+			MaxAttempts:  viper.GetInt("reminders.maxAttempts"),
+			RetryBackoff: viper.GetDuration("reminders.retryBackoff"),
+			Timeout:      viper.GetDuration("reminders.timeout"),
+		},
+		ReminderPollInterval: viper.GetDuration("reminders.pollInterval"),
+
+		RenderCacheSize: viper.GetInt("render.cacheSize"),
+	})
+	handlers := handler.NewHandler(services)
+
+	srv := new(notes.Server)
+	go func() {
+		if err := srv.Run(viper.GetString("port"), handlers.InitRoutes()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatalf("error occured while running http server: %s", err.Error())
+		}
+	}()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	for _, job := range services.Jobs {
+		jobs.Add(1)
+		go func(job service.Job) {
+			defer jobs.Done()
+			job.Start(jobsCtx)
+		}(job)
+	}
+
+	logrus.Print("notes-app started")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+	<-quit
+
+	logrus.Print("notes-app shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("shutdownTimeout"))
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logrus.Errorf("error occured on server shutting down: %s", err.Error())
+	}
+
+	stopJobs()
+	jobs.Wait()
+
+	if err := db.Close(); err != nil {
+		logrus.Errorf("error occured on db connection close: %s", err.Error())
+	}
+}
+
+// initConfig reads config/config.yml and layers the environment on top of
+// it: a missing .env file is not an error, and any key can be overridden by
+// its upper-cased, underscore-separated name (db.host -> DB_HOST).
+func initConfig() error {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	viper.AddConfigPath("config")
+	viper.SetConfigName("config")
+
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
+	for key, env := range map[string]string{
+		"db.password":             "DB_PASSWORD",
+		"auth.passwordSalt":       "PASSWORD_SALT",
+		"auth.signingKey":         "JWT_SIGNING_KEY",
+		"reminders.smtp.password": "SMTP_PASSWORD",
+	} {
+		if err := viper.BindEnv(key, env); err != nil {
+			return err
+		}
+	}
+
+	return viper.ReadInConfig()
+}
+
+// hmacKeyId is the kid of the shared-secret key built from auth.signingKey.
+const hmacKeyId = "hs256"
+
+// loadKeySet builds the JWT key set from the PEM files listed under
+// auth.keys plus the legacy shared secret. auth.activeKey selects the key
+// new tokens are signed with; the others only verify.
+func loadKeySet() (*service.KeySet, error) {
+	var files []struct {
+		Id   string
+		Path string
+	}
+	if err := viper.UnmarshalKey("auth.keys", &files); err != nil {
+		return nil, err
+	}
+
+	var keys []*service.SigningKey
+	if secret := viper.GetString("auth.signingKey"); secret != "" {
+		keys = append(keys, service.NewHMACKey(hmacKeyId, secret))
+	}
+
+	for _, f := range files {
+		key, err := service.LoadSigningKey(f.Id, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	activeKey := viper.GetString("auth.activeKey")
+	if activeKey == "" {
+		activeKey = hmacKeyId
+	}
+
+	return service.NewKeySet(activeKey, keys...)
+}
+
+// newNotifiers returns the reminder channels enabled under reminders.
+func newNotifiers(repos *repository.Repository) map[string]service.Notifier {
+	notifiers := make(map[string]service.Notifier)
+
+	if viper.GetBool("reminders.inbox") {
+		notifiers[notes.ChannelInbox] = service.NewInboxNotifier(repos.Inbox)
+	}
+
+	if addr := viper.GetString("reminders.smtp.addr"); addr != "" {
+		notifiers[notes.ChannelEmail] = service.NewSMTPNotifier(service.SMTPConfig{
+			Addr:     addr,
+			From:     viper.GetString("reminders.smtp.from"),
+			Username: viper.GetString("reminders.smtp.username"),
+			Password: viper.GetString("reminders.smtp.password"),
+		})
+	}
+
+	if url := viper.GetString("reminders.webhook.url"); url != "" {
+		notifiers[notes.ChannelWebhook] = service.NewWebhookNotifier(url)
+	}
+
+	return notifiers
+}
+
+// runMigrate executes the `migrate` subcommand against the embedded schema.
+func runMigrate(db *sqlx.DB, args []string) error {
+	migrator, err := repository.NewMigrator(db, schema.Migrations)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return errors.New("missing migrate command")
+	}
+
+	switch args[0] {
+	case "up":
+		return migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return migrator.Down(steps)
+	case "to", "force":
+		if len(args) < 2 {
+			return fmt.Errorf("%s requires a version", args[0])
+		}
+		version, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if args[0] == "force" {
+			return migrator.Force(uint(version))
+		}
+		return migrator.To(uint(version))
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d, dirty: %t\n", status.Version, status.Dirty)
+		for _, m := range status.Pending {
+			fmt.Printf("pending: %d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"fmt"
+	"time"
+)
+
+// Due date views. Overdue holds the items whose due time has passed, today
+// those due on the current day and upcoming those due in the days after.
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueUpcoming = "upcoming"
+
+	UpcomingDays = 7
+)
+
+// DueQuery selects a due date view of the open items in all of the user's
+// lists. Timezone is an IANA name that decides where days start; empty
+// means UTC.
+type DueQuery struct {
+	View     string `form:"view"`
+	Timezone string `form:"tz"`
+}
+
+func (q DueQuery) Validate() error {
+	switch q.View {
+	case DueOverdue, DueToday, DueUpcoming:
+	case "":
+		return fmt.Errorf(validationError, "due query")
+	default:
+		return fmt.Errorf("unknown view %q", q.View)
+	}
+
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown time zone %q", q.Timezone)
+	}
+
+	return nil
+}
+
+// Range returns the due times the view covers at the given time, from
+// inclusive to exclusive. A nil bound is open.
+func (q DueQuery) Range(now time.Time) (from, to *time.Time) {
+	if loc, err := time.LoadLocation(q.Timezone); err == nil {
+		now = now.In(loc)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	switch q.View {
+	case DueOverdue:
+		return nil, &now
+	case DueToday:
+		return &today, &tomorrow
+	default:
+		end := tomorrow.AddDate(0, 0, UpcomingDays)
+		return &tomorrow, &end
+	}
+}
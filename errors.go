@@ -0,0 +1,16 @@
+package notes
+
+import "errors"
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenReused        = errors.New("refresh token reuse detected, session revoked")
+	ErrForbidden          = errors.New("insufficient permissions for this list")
+
+	ErrInvitationClosed = errors.New("invitation is no longer pending")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrVersionConflict  = errors.New("resource was modified by another request")
+	ErrTagExists        = errors.New("a tag with this name already exists")
+	ErrFolderCycle      = errors.New("a folder can't be moved into itself or one of its subfolders")
+)
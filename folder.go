@@ -0,0 +1,78 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const MaxFolderName = 255
+
+// Folder groups lists. Folders are personal like tags: every member of a
+// shared list keeps it in a folder of their own, or in none.
+type Folder struct {
+	Id        int       `json:"id" db:"id"`
+	ParentId  *int      `json:"parent_id" db:"parent_id"`
+	Name      string    `json:"name" db:"name" binding:"required"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (f Folder) Validate() error {
+	return validateFolderName(f.Name)
+}
+
+type UpdateFolderInput struct {
+	Name *string `json:"name"`
+}
+
+func (inp UpdateFolderInput) Validate() error {
+	if inp.Name == nil {
+		return fmt.Errorf(validationError, "update folder input")
+	}
+
+	return validateFolderName(*inp.Name)
+}
+
+func validateFolderName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("folder name must not be empty")
+	}
+
+	if len([]rune(name)) > MaxFolderName {
+		return fmt.Errorf("folder name must be at most %d characters", MaxFolderName)
+	}
+
+	return nil
+}
+
+// MoveFolderInput names the new parent of a folder; null moves it to the
+// top level.
+type MoveFolderInput struct {
+	ParentId *int `json:"parent_id"`
+}
+
+// PlaceListInput names the folder to keep a list in; null takes it out of
+// its folder.
+type PlaceListInput struct {
+	FolderId *int `json:"folder_id"`
+}
+
+// FolderedList is a list together with the folder the user keeps it in.
+type FolderedList struct {
+	NotesList
+	FolderId *int `json:"folder_id" db:"folder_id"`
+}
+
+// FolderNode is a folder with its subfolders and lists.
+type FolderNode struct {
+	Folder
+	Folders []FolderNode `json:"folders"`
+	Lists   []NotesList  `json:"lists"`
+}
+
+// FolderTree is everything a user has organised: the top-level folders and
+// the lists that are in no folder.
+type FolderTree struct {
+	Folders []FolderNode `json:"folders"`
+	Lists   []NotesList  `json:"lists"`
+}
@@ -0,0 +1,27 @@
+package notes
+
+import "fmt"
+
+// Description formats. Plain descriptions are shown as written, markdown
+// ones as CommonMark with the GitHub extensions.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+func validateFormat(format string) error {
+	if format != FormatPlain && format != FormatMarkdown {
+		return fmt.Errorf("format must be %s or %s", FormatPlain, FormatMarkdown)
+	}
+
+	return nil
+}
+
+// RenderedItem is the description of an item version rendered as HTML that
+// is safe to embed in a page.
+type RenderedItem struct {
+	ItemId  int    `json:"item_id"`
+	Version int    `json:"version"`
+	Format  string `json:"format"`
+	HTML    string `json:"html"`
+}
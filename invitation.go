@@ -0,0 +1,44 @@
+package notes
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation offers a role on a list. An invitation without an invitee is a
+// link invitation: whoever redeems its token first becomes the invitee.
+type Invitation struct {
+	Id        int       `json:"id" db:"id"`
+	ListId    int       `json:"list_id" db:"list_id"`
+	ListTitle string    `json:"list_title" db:"list_title"`
+	Inviter   string    `json:"inviter" db:"inviter"`
+	Invitee   *string   `json:"invitee" db:"invitee"`
+	Role      string    `json:"role" db:"role"`
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	Token     string    `json:"token,omitempty" db:"-"`
+}
+
+// InviteInput invites Username to a list, or creates a link invitation when
+// Username is empty.
+type InviteInput struct {
+	Username string `json:"username"`
+	Role     string `json:"role" binding:"required"`
+}
+
+func (inp InviteInput) Validate() error {
+	if _, ok := roleRanks[inp.Role]; !ok {
+		return fmt.Errorf("unknown role %q", inp.Role)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxItemLinks caps how many distinct items a description may link to.
+	MaxItemLinks = 100
+	maxLinkRef   = 255
+
+	// Reasons a link is broken.
+	LinkMissing = "missing"
+	LinkDeleted = "deleted"
+	LinkRenamed = "renamed"
+)
+
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+
+// ItemLink is a [[link]] in the description of an item. Ref is the text
+// between the brackets: the title of the target item or its id as "#12".
+// Target and Title are those of the item the link was resolved to when its
+// description was saved, as long as the reader can see it. Broken is empty
+// for working links and otherwise tells why the link no longer works.
+type ItemLink struct {
+	SourceId int     `json:"source_id" db:"source_id"`
+	Ref      string  `json:"ref" db:"ref"`
+	TargetId *int    `json:"target_id" db:"target_id"`
+	Title    *string `json:"title" db:"title"`
+	Broken   string  `json:"broken,omitempty" db:"broken"`
+}
+
+// BrokenLink is a broken link together with the item it is in.
+type BrokenLink struct {
+	ItemLink
+	SourceTitle string `json:"source_title" db:"source_title"`
+	ListId      int    `json:"list_id" db:"list_id"`
+}
+
+// ParseLinks returns the distinct links of text, in order of appearance.
+// Titles are compared ignoring case and surrounding space; the first
+// spelling wins. Brackets holding a "#" that isn't followed by an id are
+// not links.
+func ParseLinks(text string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, m := range linkPattern.FindAllStringSubmatch(text, -1) {
+		ref, ok := linkRef(m[1])
+		if !ok || seen[strings.ToLower(ref)] {
+			continue
+		}
+
+		seen[strings.ToLower(ref)] = true
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+func linkRef(inner string) (string, bool) {
+	ref := strings.TrimSpace(inner)
+	if ref == "" || len([]rune(ref)) > maxLinkRef {
+		return "", false
+	}
+
+	if strings.HasPrefix(ref, "#") {
+		id, err := strconv.ParseInt(ref[1:], 10, 32)
+		if err != nil || id < 1 || strings.HasPrefix(ref[1:], "+") {
+			return "", false
+		}
+
+		return "#" + strconv.FormatInt(id, 10), true
+	}
+
+	return ref, true
+}
+
+func validateLinks(description string) error {
+	if len(ParseLinks(description)) > MaxItemLinks {
+		return fmt.Errorf("description must link to at most %d items", MaxItemLinks)
+	}
+
+	return nil
+}
+
+// Linkable reports whether a link to title can be written as [[title]].
+func Linkable(title string) bool {
+	ref, ok := linkRef(title)
+
+	return ok && ref == title && !strings.HasPrefix(title, "#") && !strings.ContainsAny(title, "[]\n")
+}
+
+// RewriteLinks points the links to ref in text at title instead. Text is
+// returned unchanged when title can't be linked to.
+func RewriteLinks(text, ref, title string) string {
+	if !Linkable(title) {
+		return text
+	}
+
+	return linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		found, ok := linkRef(link[2 : len(link)-2])
+		if !ok || !strings.EqualFold(found, ref) {
+			return link
+		}
+
+		return "[[" + title + "]]"
+	})
+}
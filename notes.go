@@ -1,30 +1,185 @@
 package notes
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
 const (
 	validationError = "%s did not provide required value(s)"
 )
 
+// Roles a user can hold on a list, from most to least privileged. Viewers
+// can only read, editors can also change the list and its items, and owners
+// can additionally delete the list and manage who it is shared with.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var roleRanks = map[string]int{
+	RoleOwner:  3,
+	RoleEditor: 2,
+	RoleViewer: 1,
+}
+
+// RoleAllows reports whether role grants at least the permissions of
+// required.
+func RoleAllows(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required] && roleRanks[role] > 0
+}
+
 type NotesList struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string `json:"description" db:"description"`
+	Id          int       `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title" binding:"required"`
+	Description string    `json:"description" db:"description"`
+	Role        string    `json:"role,omitempty" db:"role"`
+	Position    string    `json:"position" db:"position"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Version     int       `json:"version" db:"version"`
 }
 
 type UsersList struct {
 	Id     int
 	UserId int
 	ListId int
+	Role   string
+}
+
+type ListMember struct {
+	Username string `json:"username" db:"username"`
+	Name     string `json:"name" db:"name"`
+	Role     string `json:"role" db:"role"`
+}
+
+type ShareListInput struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+func (inp ShareListInput) Validate() error {
+	if inp.Username == "" {
+		return fmt.Errorf(validationError, "share list input")
+	}
+
+	if _, ok := roleRanks[inp.Role]; !ok {
+		return fmt.Errorf("unknown role %q", inp.Role)
+	}
+
+	return nil
 }
 
+// Item priorities, from none to the most urgent.
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
 type NotesItem struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string `json:"description" db:"description"`
-	Archived    bool   `json:"archived" db:"archived"`
+	Id          int        `json:"id" db:"id"`
+	Title       string     `json:"title" db:"title" binding:"required"`
+	Description string     `json:"description" db:"description"`
+	Format      string     `json:"format" db:"format"`
+	Archived    bool       `json:"archived" db:"archived"`
+	Position    string     `json:"position" db:"position"`
+	DueAt       *time.Time `json:"due_at" db:"due_at"`
+	Priority    int        `json:"priority" db:"priority"`
+	Done        bool       `json:"done" db:"done"`
+	DoneAt      *time.Time `json:"done_at" db:"done_at"`
+	RemindAt    *time.Time `json:"remind_at" db:"remind_at"`
+	Recurrence  *string    `json:"recurrence" db:"recurrence"`
+	Occurrence  int        `json:"occurrence" db:"occurrence"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	Version     int        `json:"version" db:"version"`
+
+	// Checklist is only filled in when listing the items of a list, for
+	// items that have a checklist.
+	Checklist *ChecklistProgress `json:"checklist,omitempty" db:"-"`
+}
+
+func (i NotesItem) Validate() error {
+	if err := validateLinks(i.Description); err != nil {
+		return err
+	}
+
+	if i.Format != "" {
+		if err := validateFormat(i.Format); err != nil {
+			return err
+		}
+	}
+
+	if i.Recurrence != nil {
+		if _, err := ParseRecurrence(*i.Recurrence); err != nil {
+			return err
+		}
+	}
+
+	return validatePriority(i.Priority)
+}
+
+func validatePriority(priority int) error {
+	if priority < PriorityNone || priority > PriorityHigh {
+		return fmt.Errorf("priority must be between %d and %d", PriorityNone, PriorityHigh)
+	}
+
+	return nil
+}
+
+// ListedItem is an item together with the list it belongs to, for views
+// that gather items from several lists.
+type ListedItem struct {
+	NotesItem
+	ListId int `json:"list_id" db:"list_id"`
+}
+
+// ReorderInput places an item or list directly after or directly before
+// another one of the same collection; exactly one of the two must be set.
+type ReorderInput struct {
+	AfterId  *int `json:"after_id"`
+	BeforeId *int `json:"before_id"`
+}
+
+func (inp ReorderInput) Validate() error {
+	if (inp.AfterId == nil) == (inp.BeforeId == nil) {
+		return fmt.Errorf(validationError, "reorder input")
+	}
+
+	return nil
+}
+
+// MaxBatchItems caps how many items one move or copy request may touch.
+const MaxBatchItems = 100
+
+// MoveItemsInput names the items to move or copy and the list they go to.
+type MoveItemsInput struct {
+	ListId  int   `json:"list_id" binding:"required"`
+	ItemIds []int `json:"item_ids" binding:"required"`
+}
+
+func (inp MoveItemsInput) Validate() error {
+	if inp.ListId <= 0 || len(inp.ItemIds) == 0 {
+		return fmt.Errorf(validationError, "move items input")
+	}
+
+	if len(inp.ItemIds) > MaxBatchItems {
+		return fmt.Errorf("at most %d items can be moved at once", MaxBatchItems)
+	}
+
+	seen := make(map[int]bool, len(inp.ItemIds))
+	for _, id := range inp.ItemIds {
+		if id <= 0 || seen[id] {
+			return fmt.Errorf("invalid or duplicate item id %d", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
 }
 
 type ListsItem struct {
@@ -46,16 +201,96 @@ func (inp UpdateListInput) Validate() (err error) {
 	return
 }
 
+// UpdateItemInput changes the given fields of an item. A due_at, remind_at
+// or recurrence of null removes it; marking an item done records when it
+// was done. Setting remind_at again arms a reminder that has already fired,
+// and setting recurrence starts the series over.
 type UpdateItemInput struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Archived    *bool   `json:"archived"`
+	Title       *string        `json:"title"`
+	Description *string        `json:"description"`
+	Format      *string        `json:"format"`
+	Archived    *bool          `json:"archived"`
+	DueAt       NullableTime   `json:"due_at"`
+	Priority    *int           `json:"priority"`
+	Done        *bool          `json:"done"`
+	RemindAt    NullableTime   `json:"remind_at"`
+	Recurrence  NullableString `json:"recurrence"`
+	// RewriteLinks makes a new title carry over to the [[links]] to the
+	// item by its old title, in the items the user can edit.
+	RewriteLinks bool `json:"rewrite_links"`
 }
 
-func (inp UpdateItemInput) Validate() (err error) {
-	if inp.Title == nil && inp.Description == nil && inp.Archived == nil {
-		err = fmt.Errorf(validationError, "update item input")
+func (inp UpdateItemInput) Validate() error {
+	if inp.Title == nil && inp.Description == nil && inp.Format == nil && inp.Archived == nil && !inp.DueAt.Set && inp.Priority == nil && inp.Done == nil && !inp.RemindAt.Set && !inp.Recurrence.Set {
+		return fmt.Errorf(validationError, "update item input")
 	}
 
-	return
+	if inp.Description != nil {
+		if err := validateLinks(*inp.Description); err != nil {
+			return err
+		}
+	}
+
+	if inp.Format != nil {
+		if err := validateFormat(*inp.Format); err != nil {
+			return err
+		}
+	}
+
+	if inp.Recurrence.String != nil {
+		if _, err := ParseRecurrence(*inp.Recurrence.String); err != nil {
+			return err
+		}
+	}
+
+	if inp.Priority != nil {
+		return validatePriority(*inp.Priority)
+	}
+
+	return nil
+}
+
+// NullableTime is a time field of an update that can be left out, set or
+// cleared with null. Set reports whether the field was present at all.
+type NullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Time = nil
+		return nil
+	}
+
+	var v time.Time
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	t.Time = &v
+
+	return nil
+}
+
+// NullableString is the string counterpart of NullableTime.
+type NullableString struct {
+	Set    bool
+	String *string
+}
+
+func (s *NullableString) UnmarshalJSON(data []byte) error {
+	s.Set = true
+	if string(data) == "null" {
+		s.String = nil
+		return nil
+	}
+
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	s.String = &v
+
+	return nil
 }
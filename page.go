@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	SortPosition = "position"
+	SortCreated  = "created"
+	SortUpdated  = "updated"
+	SortTitle    = "title"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageQuery selects one page of a collection, by default in the order the
+// user arranged it in. A Limit of 0 means DefaultPageLimit. Cursor is the
+// next_cursor of the previous page and is only valid with the same Sort and
+// Order.
+type PageQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	Order  string `form:"order"`
+}
+
+func (q PageQuery) Validate() error {
+	if q.Limit < 0 || q.Limit > MaxPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d, or 0 for the default of %d", MaxPageLimit, DefaultPageLimit)
+	}
+
+	switch q.Sort {
+	case "", SortPosition, SortCreated, SortUpdated, SortTitle:
+	default:
+		return fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	switch q.Order {
+	case "", OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("unknown order %q", q.Order)
+	}
+
+	return nil
+}
+
+// ListsQuery pages through the user's lists. ModifiedSince restricts the
+// result to lists changed after the given time, for incremental sync.
+type ListsQuery struct {
+	PageQuery
+	ModifiedSince *time.Time `form:"modified_since"`
+}
+
+// ItemsQuery pages through the items of a list, optionally only archived or
+// only active ones and only those changed after ModifiedSince.
+type ItemsQuery struct {
+	PageQuery
+	Archived      *bool      `form:"archived"`
+	ModifiedSince *time.Time `form:"modified_since"`
+}
@@ -1 +1,104 @@
 package handler
+
+import (
+	"net/http"
+
+	"github.com/Liopun/notes-app"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) signUp(c *gin.Context) {
+	var inp notes.User
+
+	if err := c.ShouldBindJSON(&inp); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	id, err := h.services.Authorization.CreateUser(inp)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type signInInput struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *Handler) signIn(c *gin.Context) {
+	var inp signInInput
+
+	if err := c.ShouldBindJSON(&inp); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	tokens, err := h.services.Authorization.GenerateToken(inp.Username, inp.Password)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (h *Handler) refresh(c *gin.Context) {
+	var inp refreshInput
+
+	if err := c.ShouldBindJSON(&inp); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	tokens, err := h.services.Authorization.RefreshToken(inp.RefreshToken)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type logoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *Handler) logout(c *gin.Context) {
+	token, err := getBearerToken(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var inp logoutInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+			return
+		}
+	}
+
+	if err := h.services.Authorization.Logout(token, inp.RefreshToken); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// jwks publishes the public token verification keys so other services can
+// validate access tokens without sharing a secret.
+func (h *Handler) jwks(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Authorization.JWKS())
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/service"
+	mock_service "github.com/Liopun/notes-app/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_signUp(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization, user notes.User)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputUser            notes.User
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name": "Test", "username": "test", "password": "qwerty"}`,
+			inputUser: notes.User{
+				Name:     "Test",
+				Username: "test",
+				Password: "qwerty",
+			},
+			mock: func(r *mock_service.MockAuthorization, user notes.User) {
+				r.EXPECT().CreateUser(user).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:                 "Missing Fields",
+			inputBody:            `{"username": "test", "password": "qwerty"}`,
+			mock:                 func(r *mock_service.MockAuthorization, user notes.User) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"name": "Test", "username": "test", "password": "qwerty"}`,
+			inputUser: notes.User{
+				Name:     "Test",
+				Username: "test",
+				Password: "qwerty",
+			},
+			mock: func(r *mock_service.MockAuthorization, user notes.User) {
+				r.EXPECT().CreateUser(user).Return(0, errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tt.mock(auth, tt.inputUser)
+
+			h := NewHandler(&service.Service{Authorization: auth})
+
+			r := gin.New()
+			r.POST("/sign-up", h.signUp)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/sign-up", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_signIn(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization, inp signInInput)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		input                signInInput
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username": "test", "password": "qwerty"}`,
+			input:     signInInput{Username: "test", Password: "qwerty"},
+			mock: func(r *mock_service.MockAuthorization, inp signInInput) {
+				r.EXPECT().GenerateToken(inp.Username, inp.Password).Return(notes.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"access_token":"access","refresh_token":"refresh"}`,
+		},
+		{
+			name:                 "Missing Password",
+			inputBody:            `{"username": "test"}`,
+			mock:                 func(r *mock_service.MockAuthorization, inp signInInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+		{
+			name:      "Wrong Credentials",
+			inputBody: `{"username": "test", "password": "wrong"}`,
+			input:     signInInput{Username: "test", Password: "wrong"},
+			mock: func(r *mock_service.MockAuthorization, inp signInInput) {
+				r.EXPECT().GenerateToken(inp.Username, inp.Password).Return(notes.Tokens{}, notes.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid username or password"}`,
+		},
+		{
+			name:      "Repository Failure",
+			inputBody: `{"username": "test", "password": "qwerty"}`,
+			input:     signInInput{Username: "test", Password: "qwerty"},
+			mock: func(r *mock_service.MockAuthorization, inp signInInput) {
+				r.EXPECT().GenerateToken(inp.Username, inp.Password).Return(notes.Tokens{}, errors.New("connection refused"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tt.mock(auth, tt.input)
+
+			h := NewHandler(&service.Service{Authorization: auth})
+
+			r := gin.New()
+			r.POST("/sign-in", h.signIn)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/sign-in", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_refresh(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"refresh_token": "refresh"}`,
+			mock: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().RefreshToken("refresh").Return(notes.Tokens{AccessToken: "access2", RefreshToken: "refresh2"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"access_token":"access2","refresh_token":"refresh2"}`,
+		},
+		{
+			name:                 "Missing Token",
+			inputBody:            `{}`,
+			mock:                 func(r *mock_service.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+		{
+			name:      "Reused Token",
+			inputBody: `{"refresh_token": "refresh"}`,
+			mock: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().RefreshToken("refresh").Return(notes.Tokens{}, notes.ErrTokenReused)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"refresh token reuse detected, session revoked"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tt.mock(auth)
+
+			h := NewHandler(&service.Service{Authorization: auth})
+
+			r := gin.New()
+			r.POST("/refresh", h.refresh)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/refresh", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_logout(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization)
+
+	tests := []struct {
+		name                 string
+		headerValue          string
+		inputBody            string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			headerValue: "Bearer access",
+			inputBody:   `{"refresh_token": "refresh"}`,
+			mock: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().Logout("access", "refresh").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:        "OK_NoBody",
+			headerValue: "Bearer access",
+			mock: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().Logout("access", "").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "No Header",
+			mock:                 func(r *mock_service.MockAuthorization) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"empty auth header"}`,
+		},
+		{
+			name:        "Invalid Token",
+			headerValue: "Bearer expired",
+			mock: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().Logout("expired", "").Return(notes.ErrInvalidToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid or expired token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tt.mock(auth)
+
+			h := NewHandler(&service.Service{Authorization: auth})
+
+			r := gin.New()
+			r.POST("/logout", h.logout)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/logout", bytes.NewBufferString(tt.inputBody))
+			if tt.headerValue != "" {
+				req.Header.Set("Authorization", tt.headerValue)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_jwks(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockAuthorization(c)
+	auth.EXPECT().JWKS().Return(notes.JSONWebKeySet{Keys: []notes.JSONWebKey{
+		{Kty: "OKP", Kid: "2026-10", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "abc"},
+	}})
+
+	h := NewHandler(&service.Service{Authorization: auth})
+
+	w := httptest.NewRecorder()
+	h.InitRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"2026-10","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"abc"}]}`, w.Body.String())
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createChecklistEntry(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"text": "buy milk"}`,
+			mock: func(m serviceMocks) {
+				m.check.EXPECT().Create(1, 4, notes.ChecklistEntry{Text: "buy milk"}).Return(7, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":7}`,
+		},
+		{
+			name:                 "Blank Text",
+			inputBody:            `{"text": "  "}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"checklist entry text must not be empty"}`,
+		},
+		{
+			name:      "Viewer",
+			inputBody: `{"text": "buy milk"}`,
+			mock: func(m serviceMocks) {
+				m.check.EXPECT().Create(1, 4, notes.ChecklistEntry{Text: "buy milk"}).Return(-1, notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/4/checklist/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getChecklist(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	r, m := newTestRouter(t)
+	m.check.EXPECT().GetAll(1, 4).Return([]notes.ChecklistEntry{
+		{Id: 7, Text: "buy milk", Checked: true, Position: "i", CreatedAt: created, UpdatedAt: created},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4/checklist/", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"id":7,"text":"buy milk","checked":true,"position":"i","created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:00:00Z"}]}`, w.Body.String())
+}
+
+func TestHandler_updateChecklistEntry(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"checked": true}`,
+			mock: func(m serviceMocks) {
+				m.check.EXPECT().Update(1, 4, 7, notes.UpdateChecklistEntryInput{Checked: boolPointer(true)}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"text": "buy oat milk"}`,
+			mock: func(m serviceMocks) {
+				m.check.EXPECT().Update(1, 4, 7, notes.UpdateChecklistEntryInput{Text: stringPointer("buy oat milk")}).Return(sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+		{
+			name:                 "Empty Input",
+			inputBody:            `{}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"update checklist entry input did not provide required value(s)"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("PATCH", "/api/items/4/checklist/7", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_reorderChecklistEntry(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.check.EXPECT().Reorder(1, 4, 7, notes.ReorderInput{AfterId: intPointer(8)}).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/4/checklist/7/reorder", bytes.NewBufferString(`{"after_id": 8}`)))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
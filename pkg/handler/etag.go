@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+var errInvalidIfMatch = errors.New(`If-Match must be "*" or a single entity tag such as "3"`)
+
+// setETag exposes the version of a list or item as its entity tag.
+func setETag(c *gin.Context, version int) {
+	c.Header(etagHeader, fmt.Sprintf(`"%d"`, version))
+}
+
+// getIfMatchVersion returns the version named by the If-Match header, or 0
+// when the header is missing or "*" and the request is unconditional. Only a
+// single entity tag is accepted, as every resource has one current version;
+// lists of tags are rejected like any other malformed header.
+func getIfMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader(ifMatchHeader))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+
+	digits := tag[1 : len(tag)-1]
+	if strings.Trim(digits, "0123456789") != "" {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.Atoi(digits)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createFolder(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name": "Project X", "parent_id": 2}`,
+			mock: func(m serviceMocks) {
+				m.fldrs.EXPECT().Create(1, notes.Folder{Name: "Project X", ParentId: intPointer(2)}).Return(3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3}`,
+		},
+		{
+			name:                 "Empty Name",
+			inputBody:            `{"name": " "}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"folder name must not be empty"}`,
+		},
+		{
+			name:      "Parent Not Found",
+			inputBody: `{"name": "Project X", "parent_id": 9}`,
+			mock: func(m serviceMocks) {
+				m.fldrs.EXPECT().Create(1, notes.Folder{Name: "Project X", ParentId: intPointer(9)}).Return(-1, sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"` + sql.ErrNoRows.Error() + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/folders/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_moveFolder(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"parent_id": 3}`,
+			mock: func(m serviceMocks) {
+				m.fldrs.EXPECT().Move(1, 2, notes.MoveFolderInput{ParentId: intPointer(3)}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "To Top Level",
+			inputBody: `{"parent_id": null}`,
+			mock: func(m serviceMocks) {
+				m.fldrs.EXPECT().Move(1, 2, notes.MoveFolderInput{}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Cycle",
+			inputBody: `{"parent_id": 5}`,
+			mock: func(m serviceMocks) {
+				m.fldrs.EXPECT().Move(1, 2, notes.MoveFolderInput{ParentId: intPointer(5)}).Return(notes.ErrFolderCycle)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"message":"a folder can't be moved into itself or one of its subfolders"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/folders/2/move", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getFolderTree(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.fldrs.EXPECT().GetTree(1).Return(notes.FolderTree{
+		Folders: []notes.FolderNode{{
+			Folder:  notes.Folder{Id: 2, Name: "Work"},
+			Folders: []notes.FolderNode{},
+			Lists:   []notes.NotesList{{Id: 4, Title: "todo"}},
+		}},
+		Lists: []notes.NotesList{},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/folders/", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"folders":[{"id":2,"parent_id":null,"name":"Work","created_at":"0001-01-01T00:00:00Z","folders":[],"lists":[{"id":4,"title":"todo","description":"","position":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0}]}],"lists":[]}`, w.Body.String())
+}
+
+func TestHandler_placeList(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.fldrs.EXPECT().PlaceList(1, 4, notes.PlaceListInput{FolderId: intPointer(2)}).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("PUT", "/api/lists/4/folder", bytes.NewBufferString(`{"folder_id": 2}`)))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -1 +1,153 @@
 package handler
+
+import (
+	"net/http"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/service"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	services *service.Service
+}
+
+func NewHandler(services *service.Service) *Handler {
+	return &Handler{services: services}
+}
+
+func (h *Handler) InitRoutes() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/.well-known/jwks.json", h.jwks)
+
+	auth := router.Group("/auth")
+	{
+		auth.POST("/sign-up", h.signUp)
+		auth.POST("/sign-in", h.signIn)
+		auth.POST("/refresh", h.refresh)
+		auth.POST("/logout", h.logout)
+	}
+
+	api := router.Group("/api", h.userIdentity)
+	{
+		lists := api.Group("/lists")
+		{
+			lists.POST("/", requireScope(notes.ScopeListsWrite), h.createList)
+			lists.GET("/", requireScope(notes.ScopeListsRead), h.getAllLists)
+			lists.GET("/:id", requireScope(notes.ScopeListsRead), h.getListById)
+			lists.PATCH("/:id", requireScope(notes.ScopeListsWrite), h.updateList)
+			lists.DELETE("/:id", requireScope(notes.ScopeListsWrite), h.deleteList)
+			lists.POST("/:id/restore", requireScope(notes.ScopeListsWrite), h.restoreList)
+			lists.POST("/:id/reorder", requireScope(notes.ScopeListsWrite), h.reorderList)
+			lists.PUT("/:id/folder", requireScope(notes.ScopeListsWrite), h.placeList)
+
+			members := lists.Group(":id/members")
+			{
+				members.GET("/", requireScope(notes.ScopeListsRead), h.getListMembers)
+				members.POST("/", requireScope(notes.ScopeListsWrite), h.shareList)
+				members.DELETE("/:username", requireScope(notes.ScopeListsWrite), h.unshareList)
+			}
+
+			invitations := lists.Group(":id/invitations")
+			{
+				invitations.POST("/", requireScope(notes.ScopeListsWrite), h.inviteToList)
+				invitations.GET("/", requireScope(notes.ScopeListsRead), h.getListInvitations)
+				invitations.DELETE("/:invitationId", requireScope(notes.ScopeListsWrite), h.revokeInvitation)
+			}
+
+			items := lists.Group(":id/items")
+			{
+				items.POST("/", requireScope(notes.ScopeItemsWrite), h.createItem)
+				items.GET("/", requireScope(notes.ScopeItemsRead), h.getAllItems)
+			}
+		}
+
+		items := api.Group("/items")
+		{
+			items.POST("/move", requireScope(notes.ScopeItemsWrite), h.moveItems)
+			items.POST("/copy", requireScope(notes.ScopeItemsWrite), h.copyItems)
+			items.GET("/due", requireScope(notes.ScopeItemsRead), h.getDueItems)
+			items.GET("/broken-links", requireScope(notes.ScopeItemsRead), h.getBrokenLinks)
+			items.GET("/:id", requireScope(notes.ScopeItemsRead), h.getItemById)
+			items.GET("/:id/html", requireScope(notes.ScopeItemsRead), h.renderItem)
+			items.PATCH("/:id", requireScope(notes.ScopeItemsWrite), h.updateItem)
+			items.DELETE("/:id", requireScope(notes.ScopeItemsWrite), h.deleteItem)
+			items.POST("/:id/restore", requireScope(notes.ScopeItemsWrite), h.restoreItem)
+			items.POST("/:id/reorder", requireScope(notes.ScopeItemsWrite), h.reorderItem)
+			items.GET("/:id/diff", requireScope(notes.ScopeItemsRead), h.diffItemRevisions)
+			items.GET("/:id/reminders", requireScope(notes.ScopeItemsRead), h.getItemReminders)
+			items.GET("/:id/links", requireScope(notes.ScopeItemsRead), h.getItemLinks)
+			items.GET("/:id/backlinks", requireScope(notes.ScopeItemsRead), h.getItemBacklinks)
+
+			itemTags := items.Group("/:id/tags")
+			{
+				itemTags.GET("/", requireScope(notes.ScopeItemsRead), h.getItemTags)
+				itemTags.PUT("/:tagId", requireScope(notes.ScopeItemsWrite), h.tagItem)
+				itemTags.DELETE("/:tagId", requireScope(notes.ScopeItemsWrite), h.untagItem)
+			}
+
+			checklist := items.Group("/:id/checklist")
+			{
+				checklist.POST("/", requireScope(notes.ScopeItemsWrite), h.createChecklistEntry)
+				checklist.GET("/", requireScope(notes.ScopeItemsRead), h.getChecklist)
+				checklist.PATCH("/:entryId", requireScope(notes.ScopeItemsWrite), h.updateChecklistEntry)
+				checklist.DELETE("/:entryId", requireScope(notes.ScopeItemsWrite), h.deleteChecklistEntry)
+				checklist.POST("/:entryId/reorder", requireScope(notes.ScopeItemsWrite), h.reorderChecklistEntry)
+			}
+
+			revisions := items.Group("/:id/revisions")
+			{
+				revisions.GET("/", requireScope(notes.ScopeItemsRead), h.getItemRevisions)
+				revisions.GET("/:version", requireScope(notes.ScopeItemsRead), h.getItemRevision)
+				revisions.POST("/:version/restore", requireScope(notes.ScopeItemsWrite), h.restoreItemRevision)
+			}
+		}
+
+		folders := api.Group("/folders")
+		{
+			folders.POST("/", requireScope(notes.ScopeListsWrite), h.createFolder)
+			folders.GET("/", requireScope(notes.ScopeListsRead), h.getFolderTree)
+			folders.PATCH("/:id", requireScope(notes.ScopeListsWrite), h.updateFolder)
+			folders.DELETE("/:id", requireScope(notes.ScopeListsWrite), h.deleteFolder)
+			folders.POST("/:id/move", requireScope(notes.ScopeListsWrite), h.moveFolder)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.POST("/", requireScope(notes.ScopeItemsWrite), h.createTag)
+			tags.GET("/", requireScope(notes.ScopeItemsRead), h.getAllTags)
+			tags.GET("/items", requireScope(notes.ScopeItemsRead), h.getTaggedItems)
+			tags.PATCH("/:id", requireScope(notes.ScopeItemsWrite), h.updateTag)
+			tags.DELETE("/:id", requireScope(notes.ScopeItemsWrite), h.deleteTag)
+			tags.POST("/:id/merge", requireScope(notes.ScopeItemsWrite), h.mergeTag)
+		}
+
+		api.GET("/search", requireScope(notes.ScopeListsRead), requireScope(notes.ScopeItemsRead), h.search)
+		api.GET("/trash", requireScope(notes.ScopeListsRead), requireScope(notes.ScopeItemsRead), h.getTrash)
+
+		inbox := api.Group("/inbox")
+		{
+			inbox.GET("/", requireScope(notes.ScopeItemsRead), h.getInbox)
+			inbox.POST("/:id/read", requireScope(notes.ScopeItemsWrite), h.readInboxMessage)
+		}
+
+		invitations := api.Group("/invitations")
+		{
+			invitations.GET("/", requireScope(notes.ScopeListsRead), h.getPendingInvitations)
+			invitations.POST("/redeem", requireScope(notes.ScopeListsWrite), h.redeemInvitation)
+			invitations.POST("/:id/accept", requireScope(notes.ScopeListsWrite), h.acceptInvitation)
+			invitations.POST("/:id/decline", requireScope(notes.ScopeListsWrite), h.declineInvitation)
+		}
+
+		tokens := api.Group("/tokens", sessionOnly)
+		{
+			tokens.POST("/", h.createPersonalToken)
+			tokens.GET("/", h.getPersonalTokens)
+			tokens.DELETE("/:id", h.revokePersonalToken)
+		}
+	}
+
+	return router
+}
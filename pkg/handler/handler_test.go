@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app/pkg/service"
+	mock_service "github.com/Liopun/notes-app/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+const testToken = "token"
+
+type serviceMocks struct {
+	auth  *mock_service.MockAuthorization
+	lists *mock_service.MockNotesList
+	items *mock_service.MockNotesItem
+	rndr  *mock_service.MockRenderer
+	invs  *mock_service.MockInvitation
+	srch  *mock_service.MockSearch
+	trash *mock_service.MockTrash
+	tags  *mock_service.MockTag
+	check *mock_service.MockChecklist
+	links *mock_service.MockLink
+	fldrs *mock_service.MockFolder
+	rems  *mock_service.MockReminder
+	inbox *mock_service.MockInbox
+}
+
+// newTestRouter builds the full route tree on top of mocked services. The
+// auth mock accepts testToken as user 1 for any request reaching /api.
+func newTestRouter(t *testing.T) (http.Handler, serviceMocks) {
+	c := gomock.NewController(t)
+	t.Cleanup(c.Finish)
+
+	m := serviceMocks{
+		auth:  mock_service.NewMockAuthorization(c),
+		lists: mock_service.NewMockNotesList(c),
+		items: mock_service.NewMockNotesItem(c),
+		rndr:  mock_service.NewMockRenderer(c),
+		invs:  mock_service.NewMockInvitation(c),
+		srch:  mock_service.NewMockSearch(c),
+		trash: mock_service.NewMockTrash(c),
+		tags:  mock_service.NewMockTag(c),
+		check: mock_service.NewMockChecklist(c),
+		links: mock_service.NewMockLink(c),
+		fldrs: mock_service.NewMockFolder(c),
+		rems:  mock_service.NewMockReminder(c),
+		inbox: mock_service.NewMockInbox(c),
+	}
+	m.auth.EXPECT().ParseToken(testToken).Return(1, nil).AnyTimes()
+
+	h := NewHandler(&service.Service{
+		Authorization: m.auth,
+		NotesList:     m.lists,
+		NotesItem:     m.items,
+		Renderer:      m.rndr,
+		Invitation:    m.invs,
+		Search:        m.srch,
+		Trash:         m.trash,
+		Tag:           m.tags,
+		Checklist:     m.check,
+		Link:          m.links,
+		Folder:        m.fldrs,
+		Reminder:      m.rems,
+		Inbox:         m.inbox,
+	})
+
+	return h.InitRoutes(), m
+}
+
+func newAuthorizedRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set(authorizationHeader, "Bearer "+testToken)
+
+	return req
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
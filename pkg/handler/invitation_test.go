@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_inviteToList(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Link",
+			inputBody: `{"role": "viewer"}`,
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Invite(1, 2, notes.InviteInput{Role: notes.RoleViewer}).Return(notes.Invitation{
+					Id:        5,
+					ListId:    2,
+					Role:      notes.RoleViewer,
+					Status:    notes.InvitationPending,
+					CreatedAt: createdAt,
+					ExpiresAt: createdAt.Add(24 * time.Hour),
+					Token:     "signed",
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":5,"list_id":2,"list_title":"","inviter":"","invitee":null,"role":"viewer","status":"pending","created_at":"2026-10-01T12:00:00Z","expires_at":"2026-10-02T12:00:00Z","token":"signed"}`,
+		},
+		{
+			name:                 "Unknown Role",
+			inputBody:            `{"username": "bob", "role": "admin"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown role \"admin\""}`,
+		},
+		{
+			name:      "Not Owner",
+			inputBody: `{"username": "bob", "role": "editor"}`,
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Invite(1, 2, notes.InviteInput{Username: "bob", Role: notes.RoleEditor}).Return(notes.Invitation{}, notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/2/invitations/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_revokeInvitation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/lists/2/invitations/5",
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Revoke(1, 2, 5).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Id",
+			target:               "/api/lists/2/invitations/abc",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid invitationId param"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("DELETE", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_acceptInvitation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Accept(1, 5).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"list_id":2}`,
+		},
+		{
+			name: "Not Found",
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Accept(1, 5).Return(-1, sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+		{
+			name: "Closed",
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().Accept(1, 5).Return(-1, notes.ErrInvitationClosed)
+			},
+			expectedStatusCode:   410,
+			expectedResponseBody: `{"message":"invitation is no longer pending"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/invitations/5/accept", nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_redeemInvitation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"token": "signed"}`,
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().AcceptToken(1, "signed").Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"list_id":2}`,
+		},
+		{
+			name:      "Invalid Token",
+			inputBody: `{"token": "forged"}`,
+			mock: func(m serviceMocks) {
+				m.invs.EXPECT().AcceptToken(1, "forged").Return(-1, notes.ErrInvalidToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid or expired token"}`,
+		},
+		{
+			name:                 "Missing Token",
+			inputBody:            `{}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/invitations/redeem", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Liopun/notes-app"
+	"github.com/gin-gonic/gin"
+)
+
+type getLinksResponse struct {
+	Data []notes.ItemLink `json:"data"`
+}
+
+type getBacklinksResponse struct {
+	Data []notes.ListedItem `json:"data"`
+}
+
+type getBrokenLinksResponse struct {
+	Data []notes.BrokenLink `json:"data"`
+}
+
+func (h *Handler) getItemLinks(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := getIdParam(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	links, err := h.services.Link.GetLinks(userId, itemId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getLinksResponse{
+		Data: links,
+	})
+}
+
+func (h *Handler) getItemBacklinks(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := getIdParam(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.services.Link.GetBacklinks(userId, itemId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getBacklinksResponse{
+		Data: items,
+	})
+}
+
+func (h *Handler) getBrokenLinks(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	links, err := h.services.Link.GetBroken(userId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getBrokenLinksResponse{
+		Data: links,
+	})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getItemLinks(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.links.EXPECT().GetLinks(1, 4).Return([]notes.ItemLink{
+					{SourceId: 4, Ref: "#7", TargetId: intPointer(7), Title: stringPointer("groceries")},
+					{SourceId: 4, Ref: "Shopping", TargetId: intPointer(8), Title: stringPointer("Errands"), Broken: notes.LinkRenamed},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"source_id":4,"ref":"#7","target_id":7,"title":"groceries"},{"source_id":4,"ref":"Shopping","target_id":8,"title":"Errands","broken":"renamed"}]}`,
+		},
+		{
+			name: "Forbidden",
+			mock: func(m serviceMocks) {
+				m.links.EXPECT().GetLinks(1, 4).Return(nil, notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4/links", nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getItemBacklinks(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.links.EXPECT().GetBacklinks(1, 4).Return([]notes.ListedItem{{NotesItem: notes.NotesItem{Id: 9, Title: "plan"}, ListId: 2}}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4/backlinks", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"id":9,"title":"plan","description":"","format":"","archived":false,"position":"","due_at":null,"priority":0,"done":false,"done_at":null,"remind_at":null,"recurrence":null,"occurrence":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0,"list_id":2}]}`, w.Body.String())
+}
+
+func TestHandler_getBrokenLinks(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.links.EXPECT().GetBroken(1).Return([]notes.BrokenLink{{
+		ItemLink:    notes.ItemLink{SourceId: 9, Ref: "#7", Broken: notes.LinkMissing},
+		SourceTitle: "plan",
+		ListId:      2,
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/broken-links", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"source_id":9,"ref":"#7","target_id":null,"title":null,"broken":"missing","source_title":"plan","list_id":2}]}`, w.Body.String())
+}
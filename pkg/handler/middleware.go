@@ -1 +1,114 @@
 package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	userCtx             = "userId"
+	scopesCtx           = "scopes"
+)
+
+func (h *Handler) userIdentity(c *gin.Context) {
+	token, err := getBearerToken(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if strings.HasPrefix(token, notes.PersonalTokenPrefix) {
+		userId, scopes, err := h.services.Authorization.ParsePersonalToken(token)
+		if err != nil {
+			newErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		c.Set(userCtx, userId)
+		c.Set(scopesCtx, scopes)
+		return
+	}
+
+	userId, err := h.services.Authorization.ParseToken(token)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	c.Set(userCtx, userId)
+}
+
+// requireScope restricts a route for personal access tokens to those granted
+// the scope. Requests authenticated with a session token hold every scope.
+func requireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := c.Get(scopesCtx)
+		if !ok {
+			return
+		}
+
+		if !scopes.(notes.Scopes).Has(scope) {
+			newErrorResponse(c, http.StatusForbidden, fmt.Sprintf("token lacks the %s scope", scope))
+		}
+	}
+}
+
+// sessionOnly rejects requests made with a personal access token, so a
+// leaked token cannot be used to mint or revoke others.
+func sessionOnly(c *gin.Context) {
+	if _, ok := c.Get(scopesCtx); ok {
+		newErrorResponse(c, http.StatusForbidden, "personal access tokens cannot be used here")
+	}
+}
+
+func getBearerToken(c *gin.Context) (string, error) {
+	header := c.GetHeader(authorizationHeader)
+	if header == "" {
+		return "", errors.New("empty auth header")
+	}
+
+	headerParts := strings.Split(header, " ")
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return "", errors.New("invalid auth header")
+	}
+
+	if headerParts[1] == "" {
+		return "", errors.New("token is empty")
+	}
+
+	return headerParts[1], nil
+}
+
+func getUserId(c *gin.Context) (int, error) {
+	id, ok := c.Get(userCtx)
+	if !ok {
+		return 0, errors.New("user id not found")
+	}
+
+	idInt, ok := id.(int)
+	if !ok {
+		return 0, errors.New("user id is of invalid type")
+	}
+
+	return idInt, nil
+}
+
+func getIdParam(c *gin.Context) (int, error) {
+	return getIntParam(c, "id")
+}
+
+func getIntParam(c *gin.Context, name string) (int, error) {
+	value, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s param", name)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app/pkg/service"
+	mock_service "github.com/Liopun/notes-app/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_userIdentity(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization, token string)
+
+	tests := []struct {
+		name                 string
+		headerName           string
+		headerValue          string
+		token                string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mock: func(r *mock_service.MockAuthorization, token string) {
+				r.EXPECT().ParseToken(token).Return(1, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "1",
+		},
+		{
+			name:                 "No Header",
+			headerName:           "",
+			mock:                 func(r *mock_service.MockAuthorization, token string) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"empty auth header"}`,
+		},
+		{
+			name:                 "Invalid Bearer",
+			headerName:           "Authorization",
+			headerValue:          "Bearr token",
+			mock:                 func(r *mock_service.MockAuthorization, token string) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid auth header"}`,
+		},
+		{
+			name:                 "Empty Token",
+			headerName:           "Authorization",
+			headerValue:          "Bearer ",
+			mock:                 func(r *mock_service.MockAuthorization, token string) {},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"token is empty"}`,
+		},
+		{
+			name:        "Parse Failure",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mock: func(r *mock_service.MockAuthorization, token string) {
+				r.EXPECT().ParseToken(token).Return(0, errors.New("invalid token"))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tt.mock(auth, tt.token)
+
+			h := NewHandler(&service.Service{Authorization: auth})
+
+			r := gin.New()
+			r.GET("/identity", h.userIdentity, func(c *gin.Context) {
+				id, _ := c.Get(userCtx)
+				c.String(200, fmt.Sprintf("%d", id.(int)))
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/identity", nil)
+			if tt.headerName != "" {
+				req.Header.Set(tt.headerName, tt.headerValue)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetUserId(t *testing.T) {
+	getContext := func(id interface{}) *gin.Context {
+		ctx := &gin.Context{}
+		if id != nil {
+			ctx.Set(userCtx, id)
+		}
+
+		return ctx
+	}
+
+	tests := []struct {
+		name    string
+		ctx     *gin.Context
+		id      int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			ctx:  getContext(1),
+			id:   1,
+		},
+		{
+			name:    "Empty",
+			ctx:     getContext(nil),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Type",
+			ctx:     getContext("1"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := getUserId(tt.ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,532 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func boolPointer(b bool) *bool {
+	return &b
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func TestHandler_createItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"title": "title", "description": "description"}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Create(1, 3, notes.NotesItem{Title: "title", Description: "description"}).Return(7, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":7}`,
+		},
+		{
+			name:                 "Invalid Body",
+			inputBody:            `{"title": 1}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/3/items/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAllItems(t *testing.T) {
+	archived := false
+	since := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/lists/3/items/",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().GetAll(1, 3, notes.ItemsQuery{}).Return([]notes.NotesItem{
+					{Id: 1, Title: "title", Description: "description", Archived: true},
+				}, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":1,"title":"title","description":"description","format":"","archived":true,"position":"","due_at":null,"priority":0,"done":false,"done_at":null,"remind_at":null,"recurrence":null,"occurrence":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0}]}`,
+		},
+		{
+			name:   "Archived Filter",
+			target: "/api/lists/3/items/?archived=false&sort=updated&limit=10",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().GetAll(1, 3, notes.ItemsQuery{Archived: &archived, PageQuery: notes.PageQuery{Sort: "updated", Limit: 10}}).Return(nil, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":null}`,
+		},
+		{
+			name:   "Modified Since",
+			target: "/api/lists/3/items/?modified_since=2026-10-01T12:00:00Z",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().GetAll(1, 3, notes.ItemsQuery{ModifiedSince: &since}).Return(nil, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":null}`,
+		},
+		{
+			name:                 "Invalid Modified Since",
+			target:               "/api/lists/3/items/?modified_since=yesterday",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params"}`,
+		},
+		{
+			name:                 "Limit Too Large",
+			target:               "/api/lists/3/items/?limit=500",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"limit must be between 1 and 200, or 0 for the default of 50"}`,
+		},
+		{
+			name:   "Service Failure",
+			target: "/api/lists/3/items/",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().GetAll(1, 3, notes.ItemsQuery{}).Return(nil, "", errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getItemById(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.items.EXPECT().GetById(1, 4).Return(notes.NotesItem{Id: 4, Title: "title", Version: 3}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id":4,"title":"title","description":"","format":"","archived":false,"position":"","due_at":null,"priority":0,"done":false,"done_at":null,"remind_at":null,"recurrence":null,"occurrence":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":3}`, w.Body.String())
+	assert.Equal(t, `"3"`, w.Header().Get(etagHeader))
+}
+
+func TestHandler_renderItem(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.rndr.EXPECT().Render(1, 4).Return(notes.RenderedItem{ItemId: 4, Version: 3, Format: notes.FormatMarkdown, HTML: "<p><strong>milk</strong></p>\n"}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4/html", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"item_id":4,"version":3,"format":"markdown","html":"\u003cp\u003e\u003cstrong\u003emilk\u003c/strong\u003e\u003c/p\u003e\n"}`, w.Body.String())
+	assert.Equal(t, `"3"`, w.Header().Get(etagHeader))
+}
+
+func TestHandler_updateItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		ifMatch              string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedETag         string
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"archived": true}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{Archived: boolPointer(true)}, 0).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"2"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Rewrite Links",
+			inputBody: `{"title": "Groceries", "rewrite_links": true}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{Title: stringPointer("Groceries"), RewriteLinks: true}, 0).Return(3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"3"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "If-Match",
+			inputBody: `{"archived": true}`,
+			ifMatch:   `"3"`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{Archived: boolPointer(true)}, 3).Return(4, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"4"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Version Conflict",
+			inputBody: `{"archived": true}`,
+			ifMatch:   `"3"`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{Archived: boolPointer(true)}, 3).Return(-1, notes.ErrVersionConflict)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"resource was modified by another request"}`,
+		},
+		{
+			name:                 "Invalid If-Match",
+			inputBody:            `{"archived": true}`,
+			ifMatch:              `"abc"`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"If-Match must be \"*\" or a single entity tag such as \"3\""}`,
+		},
+		{
+			name:                 "Unbalanced If-Match",
+			inputBody:            `{"archived": true}`,
+			ifMatch:              `"3`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"If-Match must be \"*\" or a single entity tag such as \"3\""}`,
+		},
+		{
+			name:                 "Unquoted If-Match",
+			inputBody:            `{"archived": true}`,
+			ifMatch:              `3`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"If-Match must be \"*\" or a single entity tag such as \"3\""}`,
+		},
+		{
+			name:      "Clear Due Date",
+			inputBody: `{"due_at": null, "done": true}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{DueAt: notes.NullableTime{Set: true}, Done: boolPointer(true)}, 0).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"2"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Format",
+			inputBody: `{"format": "markdown"}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Update(1, 4, notes.UpdateItemInput{Format: stringPointer(notes.FormatMarkdown)}, 0).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"2"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Format",
+			inputBody:            `{"format": "html"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"format must be plain or markdown"}`,
+		},
+		{
+			name:                 "Invalid Recurrence",
+			inputBody:            `{"recurrence": "FREQ=YEARLY"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unsupported recurrence frequency \"YEARLY\""}`,
+		},
+		{
+			name:                 "Invalid Priority",
+			inputBody:            `{"priority": 5}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"priority must be between 0 and 3"}`,
+		},
+		{
+			name:                 "Empty Input",
+			inputBody:            `{}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"update item input did not provide required value(s)"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			req := newAuthorizedRequest("PATCH", "/api/items/4", bytes.NewBufferString(tt.inputBody))
+			if tt.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, tt.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get(etagHeader))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		ifMatch              string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Delete(1, 4, 0).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Weak If-Match",
+			ifMatch: `W/"2"`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Delete(1, 4, 2).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Version Conflict",
+			ifMatch: `"2"`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Delete(1, 4, 2).Return(notes.ErrVersionConflict)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"resource was modified by another request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			req := newAuthorizedRequest("DELETE", "/api/items/4", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, tt.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_unauthorized(t *testing.T) {
+	r, _ := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/items/4", nil))
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, `{"message":"empty auth header"}`, w.Body.String())
+}
+
+func TestHandler_moveItems(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"list_id": 2, "item_ids": [4, 5]}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().MoveItems(1, notes.MoveItemsInput{ListId: 2, ItemIds: []int{4, 5}}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Duplicate Ids",
+			inputBody:            `{"list_id": 2, "item_ids": [4, 4]}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid or duplicate item id 4"}`,
+		},
+		{
+			name:      "Forbidden",
+			inputBody: `{"list_id": 2, "item_ids": [4]}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().MoveItems(1, notes.MoveItemsInput{ListId: 2, ItemIds: []int{4}}).Return(notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/move", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_copyItems(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.items.EXPECT().CopyItems(1, notes.MoveItemsInput{ListId: 2, ItemIds: []int{4, 5}}).Return([]int{10, 11}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/copy", bytes.NewBufferString(`{"list_id": 2, "item_ids": [4, 5]}`)))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"ids":[10,11]}`, w.Body.String())
+}
+
+func TestHandler_reorderItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"after_id": 3}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Reorder(1, 2, notes.ReorderInput{AfterId: intPointer(3)}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Both Anchors",
+			inputBody:            `{"after_id": 3, "before_id": 4}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"reorder input did not provide required value(s)"}`,
+		},
+		{
+			name:      "Forbidden",
+			inputBody: `{"before_id": 3}`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().Reorder(1, 2, notes.ReorderInput{BeforeId: intPointer(3)}).Return(notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/2/reorder", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getDueItems(t *testing.T) {
+	due := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/items/due?view=today&tz=Europe/Berlin",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().GetDue(1, notes.DueQuery{View: notes.DueToday, Timezone: "Europe/Berlin"}).
+					Return([]notes.ListedItem{{NotesItem: notes.NotesItem{Id: 4, Title: "title", DueAt: &due, Priority: notes.PriorityLow}, ListId: 7}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":4,"title":"title","description":"","format":"","archived":false,"position":"","due_at":"2026-10-01T09:00:00Z","priority":1,"done":false,"done_at":null,"remind_at":null,"recurrence":null,"occurrence":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0,"list_id":7}]}`,
+		},
+		{
+			name:                 "Unknown View",
+			target:               "/api/items/due?view=later",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown view \"later\""}`,
+		},
+		{
+			name:                 "Unknown Time Zone",
+			target:               "/api/items/due?view=overdue&tz=Mars/Olympus",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown time zone \"Mars/Olympus\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
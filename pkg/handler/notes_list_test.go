@@ -0,0 +1,412 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func TestHandler_createList(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"title": "title", "description": "description"}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Create(1, notes.NotesList{Title: "title", Description: "description"}).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":2}`,
+		},
+		{
+			name:                 "Missing Title",
+			inputBody:            `{"description": "description"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"title": "title"}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Create(1, notes.NotesList{Title: "title"}).Return(0, errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAllLists(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/lists/",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetAll(1, notes.ListsQuery{}).Return([]notes.NotesList{
+					{Id: 1, Title: "title1", Description: "description1", CreatedAt: created, UpdatedAt: created},
+					{Id: 2, Title: "title2", CreatedAt: created, UpdatedAt: created},
+				}, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":1,"title":"title1","description":"description1","position":"","created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:00:00Z","version":0},{"id":2,"title":"title2","description":"","position":"","created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:00:00Z","version":0}]}`,
+		},
+		{
+			name:   "Next Page",
+			target: "/api/lists/?limit=1&sort=title&order=desc&cursor=abc",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetAll(1, notes.ListsQuery{PageQuery: notes.PageQuery{Limit: 1, Sort: "title", Order: "desc", Cursor: "abc"}}).Return([]notes.NotesList{
+					{Id: 2, Title: "title2", CreatedAt: created, UpdatedAt: created},
+				}, "def", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":2,"title":"title2","description":"","position":"","created_at":"2026-10-01T12:00:00Z","updated_at":"2026-10-01T12:00:00Z","version":0}],"next_cursor":"def"}`,
+		},
+		{
+			name:                 "Unknown Sort",
+			target:               "/api/lists/?sort=size",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown sort \"size\""}`,
+		},
+		{
+			name:   "Invalid Cursor",
+			target: "/api/lists/?cursor=abc",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetAll(1, notes.ListsQuery{PageQuery: notes.PageQuery{Cursor: "abc"}}).Return(nil, "", notes.ErrInvalidCursor)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid cursor"}`,
+		},
+		{
+			name:   "Service Failure",
+			target: "/api/lists/",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetAll(1, notes.ListsQuery{}).Return(nil, "", errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getListById(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/lists/1",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetById(1, 1).Return(notes.NotesList{Id: 1, Title: "title"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"title":"title","description":"","position":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0}`,
+		},
+		{
+			name:                 "Invalid Id",
+			target:               "/api/lists/abc",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid id param"}`,
+		},
+		{
+			name:   "Not Found",
+			target: "/api/lists/5",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetById(1, 5).Return(notes.NotesList{}, sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_updateList(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"title": "updated"}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Update(1, 1, notes.UpdateListInput{Title: stringPointer("updated")}, 0).Return(2, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Empty Input",
+			inputBody:            `{}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"update list input did not provide required value(s)"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("PATCH", "/api/lists/1", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteList(t *testing.T) {
+	tests := []struct {
+		name                 string
+		ifMatch              string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Delete(1, 1, 0).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Service Failure",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Delete(1, 1, 0).Return(errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"internal server error"}`,
+		},
+		{
+			name:    "Version Conflict",
+			ifMatch: `"2"`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Delete(1, 1, 2).Return(notes.ErrVersionConflict)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"resource was modified by another request"}`,
+		},
+		{
+			name:                 "If-Match List",
+			ifMatch:              `"2", "3"`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"If-Match must be \"*\" or a single entity tag such as \"3\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			req := newAuthorizedRequest("DELETE", "/api/lists/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, tt.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_shareList(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username": "bob", "role": "editor"}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Share(1, 1, notes.ShareListInput{Username: "bob", Role: "editor"}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Unknown Role",
+			inputBody:            `{"username": "bob", "role": "admin"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown role \"admin\""}`,
+		},
+		{
+			name:      "Not Owner",
+			inputBody: `{"username": "bob", "role": "viewer"}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Share(1, 1, notes.ShareListInput{Username: "bob", Role: "viewer"}).Return(notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/1/members/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getListMembers(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.lists.EXPECT().GetMembers(1, 1).Return([]notes.ListMember{
+		{Username: "alice", Name: "Alice", Role: "owner"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/lists/1/members/", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"username":"alice","name":"Alice","role":"owner"}]}`, w.Body.String())
+}
+
+func TestHandler_unshareList(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.lists.EXPECT().Unshare(1, 1, "bob").Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("DELETE", "/api/lists/1/members/bob", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHandler_reorderList(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"before_id": 3}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Reorder(1, 2, notes.ReorderInput{BeforeId: intPointer(3)}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "No Anchor",
+			inputBody:            `{}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"reorder input did not provide required value(s)"}`,
+		},
+		{
+			name:      "Unknown Anchor",
+			inputBody: `{"after_id": 9}`,
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().Reorder(1, 2, notes.ReorderInput{AfterId: intPointer(9)}).Return(sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/2/reorder", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
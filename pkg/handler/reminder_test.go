@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getItemReminders(t *testing.T) {
+	remindAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	reason := "connection refused"
+
+	tests := []struct {
+		name                 string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.rems.EXPECT().GetDeliveries(1, 5).Return([]notes.ReminderDelivery{
+					{Id: 3, ItemId: 5, Channel: notes.ChannelEmail, RemindAt: remindAt, Status: notes.DeliveryPending, Attempts: 1, LastError: &reason, NextAttemptAt: remindAt.Add(time.Minute)},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":3,"item_id":5,"channel":"email","remind_at":"2026-10-01T09:00:00Z","status":"pending","attempts":1,"last_error":"connection refused","next_attempt_at":"2026-10-01T09:01:00Z","delivered_at":null}]}`,
+		},
+		{
+			name: "Forbidden",
+			mock: func(m serviceMocks) {
+				m.rems.EXPECT().GetDeliveries(1, 5).Return(nil, notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"insufficient permissions for this list"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/5/reminders", nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getInbox(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	itemId := 5
+
+	r, m := newTestRouter(t)
+	m.inbox.EXPECT().GetAll(1).Return([]notes.InboxMessage{
+		{Id: 2, UserId: 1, ItemId: &itemId, Title: "Reminder: item", Body: "item\n", CreatedAt: createdAt},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/inbox/", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"id":2,"item_id":5,"title":"Reminder: item","body":"item\n","created_at":"2026-10-01T09:00:00Z","read_at":null}]}`, w.Body.String())
+}
+
+func TestHandler_readInboxMessage(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.inbox.EXPECT().MarkRead(1, 2).Return(sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/inbox/2/read", nil))
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, `{"message":"sql: no rows in result set"}`, w.Body.String())
+}
@@ -1 +1,57 @@
 package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Liopun/notes-app"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+func newErrorResponse(c *gin.Context, statusCode int, message string) {
+	logrus.Error(message)
+	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message})
+}
+
+// newServiceErrorResponse maps errors returned by the service layer onto
+// the matching HTTP status before writing the error envelope. Errors it
+// doesn't recognise are logged and answered with a generic message, since
+// they may carry driver messages and SQL details.
+func newServiceErrorResponse(c *gin.Context, err error) {
+	statusCode := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, notes.ErrInvalidCredentials), errors.Is(err, notes.ErrInvalidToken), errors.Is(err, notes.ErrTokenReused):
+		statusCode = http.StatusUnauthorized
+	case errors.Is(err, notes.ErrForbidden):
+		statusCode = http.StatusForbidden
+	case errors.Is(err, notes.ErrInvalidCursor):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, notes.ErrVersionConflict):
+		statusCode = http.StatusPreconditionFailed
+	case errors.Is(err, notes.ErrInvitationClosed):
+		statusCode = http.StatusGone
+	case errors.Is(err, notes.ErrTagExists), errors.Is(err, notes.ErrFolderCycle):
+		statusCode = http.StatusConflict
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		logrus.Error(err.Error())
+		c.AbortWithStatusJSON(statusCode, errorResponse{Message: "internal server error"})
+		return
+	}
+
+	newErrorResponse(c, statusCode, err.Error())
+}
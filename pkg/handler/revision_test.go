@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getItemRevisions(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	r, m := newTestRouter(t)
+	m.items.EXPECT().GetRevisions(1, 4).Return([]notes.ItemRevision{
+		{ItemId: 4, Version: 2, Title: "new", Author: stringPointer("alice"), CreatedAt: created},
+		{ItemId: 4, Version: 1, Title: "old", CreatedAt: created},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/items/4/revisions/", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"item_id":4,"version":2,"title":"new","archived":false,"author":"alice","created_at":"2026-10-01T12:00:00Z"},{"item_id":4,"version":1,"title":"old","archived":false,"author":null,"created_at":"2026-10-01T12:00:00Z"}]}`, w.Body.String())
+}
+
+func TestHandler_diffItemRevisions(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/items/4/diff?from=1&to=2",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().DiffRevisions(1, 4, 1, 2).Return(notes.RevisionDiff{
+					From:  1,
+					To:    2,
+					Title: []notes.DiffLine{{Op: notes.DiffDelete, Text: "old"}, {Op: notes.DiffInsert, Text: "new"}},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"from":1,"to":2,"title":[{"op":"delete","text":"old"},{"op":"insert","text":"new"}],"description":null,"archived_from":false,"archived_to":false}`,
+		},
+		{
+			name:                 "Missing Versions",
+			target:               "/api/items/4/diff?from=1",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params"}`,
+		},
+		{
+			name:   "Unknown Revision",
+			target: "/api/items/4/diff?from=1&to=9",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().DiffRevisions(1, 4, 1, 9).Return(notes.RevisionDiff{}, sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_restoreItemRevision(t *testing.T) {
+	tests := []struct {
+		name                 string
+		ifMatch              string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedETag         string
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().RestoreRevision(1, 4, 2, 0).Return(5, nil)
+			},
+			expectedStatusCode:   200,
+			expectedETag:         `"5"`,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Version Conflict",
+			ifMatch: `"3"`,
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().RestoreRevision(1, 4, 2, 3).Return(-1, notes.ErrVersionConflict)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"resource was modified by another request"}`,
+		},
+		{
+			name: "Forbidden",
+			mock: func(m serviceMocks) {
+				m.items.EXPECT().RestoreRevision(1, 4, 2, 0).Return(-1, notes.ErrForbidden)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"` + notes.ErrForbidden.Error() + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			req := newAuthorizedRequest("POST", "/api/items/4/revisions/2/restore", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(ifMatchHeader, tt.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get(etagHeader))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
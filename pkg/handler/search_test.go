@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_search(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/search?q=milk&limit=5",
+			mock: func(m serviceMocks) {
+				m.srch.EXPECT().Search(1, notes.SearchInput{Query: "milk", Limit: 5}).Return([]notes.SearchResult{
+					{Type: notes.SearchResultItem, Id: 4, ListId: 1, Title: "groceries", Snippet: "buy <mark>milk</mark>", Rank: 0.5},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"type":"item","id":4,"list_id":1,"title":"groceries","snippet":"buy \u003cmark\u003emilk\u003c/mark\u003e","rank":0.5}]}`,
+		},
+		{
+			name:                 "Missing Query",
+			target:               "/api/search",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"search input did not provide required value(s)"}`,
+		},
+		{
+			name:                 "Limit Too Large",
+			target:               "/api/search?q=milk&limit=1000",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"limit must be between 1 and 100, or 0 for the default of 20"}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			target:               "/api/search?q=milk&limit=many",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createTag(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name": "work", "color": "#ff0000"}`,
+			mock: func(m serviceMocks) {
+				m.tags.EXPECT().Create(1, notes.Tag{Name: "work", Color: "#ff0000"}).Return(3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3}`,
+		},
+		{
+			name:                 "Bad Color",
+			inputBody:            `{"name": "work", "color": "red"}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"color must look like #rrggbb"}`,
+		},
+		{
+			name:      "Duplicate",
+			inputBody: `{"name": "Work"}`,
+			mock: func(m serviceMocks) {
+				m.tags.EXPECT().Create(1, notes.Tag{Name: "Work"}).Return(-1, notes.ErrTagExists)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"message":"a tag with this name already exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/tags/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_mergeTag(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"into_id": 3}`,
+			mock: func(m serviceMocks) {
+				m.tags.EXPECT().Merge(1, 2, notes.MergeTagInput{IntoId: 3}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Into Itself",
+			inputBody:            `{"into_id": 2}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"a tag can't be merged into itself"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/tags/2/merge", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getTaggedItems(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			target: "/api/tags/items?tag=2&tag=3&match=any",
+			mock: func(m serviceMocks) {
+				m.tags.EXPECT().GetItems(1, notes.TaggedItemsQuery{TagIds: []int{2, 3}, Match: notes.TagMatchAny}).
+					Return([]notes.ListedItem{{NotesItem: notes.NotesItem{Id: 4, Title: "title"}, ListId: 7}}, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":4,"title":"title","description":"","format":"","archived":false,"position":"","due_at":null,"priority":0,"done":false,"done_at":null,"remind_at":null,"recurrence":null,"occurrence":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","version":0,"list_id":7}]}`,
+		},
+		{
+			name:                 "No Tags",
+			target:               "/api/tags/items",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"tagged items query did not provide required value(s)"}`,
+		},
+		{
+			name:                 "Unknown Match",
+			target:               "/api/tags/items?tag=2&match=some",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown match \"some\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_tagItem(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.tags.EXPECT().TagItem(1, 4, 2).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("PUT", "/api/items/4/tags/2", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPersonalToken = notes.PersonalTokenPrefix + "secret"
+
+func TestHandler_createPersonalToken(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name": "backup", "scopes": ["lists:read", "items:read"]}`,
+			mock: func(m serviceMocks) {
+				m.auth.EXPECT().CreatePersonalToken(1, notes.CreateTokenInput{
+					Name:   "backup",
+					Scopes: notes.Scopes{notes.ScopeListsRead, notes.ScopeItemsRead},
+				}).Return(notes.PersonalAccessToken{
+					Id:        3,
+					Name:      "backup",
+					Scopes:    notes.Scopes{notes.ScopeListsRead, notes.ScopeItemsRead},
+					Token:     "nap_abc",
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3,"name":"backup","scopes":["lists:read","items:read"],"token":"nap_abc","created_at":"2026-10-01T12:00:00Z","last_used_at":null,"expires_at":null}`,
+		},
+		{
+			name:                 "Unknown Scope",
+			inputBody:            `{"name": "backup", "scopes": ["admin"]}`,
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"unknown scope \"admin\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/tokens/", bytes.NewBufferString(tt.inputBody)))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_revokePersonalToken(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mock: func(m serviceMocks) {
+				m.auth.EXPECT().RevokePersonalToken(1, 3).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Not Found",
+			mock: func(m serviceMocks) {
+				m.auth.EXPECT().RevokePersonalToken(1, 3).Return(sql.ErrNoRows)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			tt.mock(m)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newAuthorizedRequest("DELETE", "/api/tokens/3", nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_personalTokenScopes(t *testing.T) {
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		mock                 func(m serviceMocks)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Granted",
+			method: "GET",
+			target: "/api/lists/",
+			mock: func(m serviceMocks) {
+				m.lists.EXPECT().GetAll(1, notes.ListsQuery{}).Return(nil, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":null}`,
+		},
+		{
+			name:                 "Missing Scope",
+			method:               "DELETE",
+			target:               "/api/lists/1",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"token lacks the lists:write scope"}`,
+		},
+		{
+			name:                 "Marking Read Is A Write",
+			method:               "POST",
+			target:               "/api/inbox/2/read",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"token lacks the items:write scope"}`,
+		},
+		{
+			name:                 "Token Management",
+			method:               "GET",
+			target:               "/api/tokens/",
+			mock:                 func(m serviceMocks) {},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"personal access tokens cannot be used here"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, m := newTestRouter(t)
+			m.auth.EXPECT().ParsePersonalToken(testPersonalToken).Return(1, notes.Scopes{notes.ScopeListsRead}, nil)
+			tt.mock(m)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set(authorizationHeader, "Bearer "+testPersonalToken)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	purgeAt := deletedAt.Add(720 * time.Hour)
+
+	r, m := newTestRouter(t)
+	m.trash.EXPECT().GetAll(1).Return([]notes.TrashEntry{
+		{Type: notes.TrashItem, Id: 5, ListId: 2, Title: "item", DeletedAt: deletedAt, PurgeAt: &purgeAt},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("GET", "/api/trash", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"type":"item","id":5,"list_id":2,"title":"item","deleted_at":"2026-10-01T12:00:00Z","purge_at":"2026-10-31T12:00:00Z"}]}`, w.Body.String())
+}
+
+func TestHandler_restoreList(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.lists.EXPECT().Restore(1, 2).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/lists/2/restore", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHandler_restoreItem(t *testing.T) {
+	r, m := newTestRouter(t)
+	m.items.EXPECT().Restore(1, 5).Return(sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuthorizedRequest("POST", "/api/items/5/restore", nil))
+
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, `{"message":"sql: no rows in result set"}`, w.Body.String())
+}
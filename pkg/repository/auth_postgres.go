@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Liopun/notes-app"
 	"github.com/jmoiron/sqlx"
@@ -17,9 +20,9 @@ func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
 
 func (r *AuthPostgres) CreateUser(user notes.User) (int, error) {
 	var id int
-	query := fmt.Sprintf("INSERT INTO %s (name, username, hashed_password) values ($1, $2, $3) RETURNING id", usersTable)
+	query := fmt.Sprintf("INSERT INTO %s (name, username, hashed_password, email) values ($1, $2, $3, NULLIF($4, '')) RETURNING id", usersTable)
 
-	row := r.db.QueryRow(query, user.Name, user.Username, user.Password)
+	row := r.db.QueryRow(query, user.Name, user.Username, user.Password, user.Email)
 
 	if err := row.Scan(&id); err != nil {
 		return -1, err
@@ -28,11 +31,181 @@ func (r *AuthPostgres) CreateUser(user notes.User) (int, error) {
 	return id, nil
 }
 
-func (r *AuthPostgres) GetUser(username, password string) (notes.User, error) {
+func (r *AuthPostgres) GetUser(username string) (notes.User, error) {
 	var user notes.User
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND hashed_password=$2", usersTable)
+	query := fmt.Sprintf("SELECT id, name, username, hashed_password AS password, created_at, updated_at FROM %s WHERE username=$1", usersTable)
 
-	err := r.db.Get(&user, query, username, password)
+	err := r.db.Get(&user, query, username)
 
 	return user, err
 }
+
+func (r *AuthPostgres) UpdatePasswordHash(userId int, hash string) error {
+	query := fmt.Sprintf("UPDATE %s SET hashed_password=$1, updated_at=now() WHERE id=$2", usersTable)
+
+	_, err := r.db.Exec(query, hash, userId)
+
+	return err
+}
+
+func (r *AuthPostgres) CreateRefreshToken(token notes.RefreshToken) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)", refreshTokensTable)
+
+	_, err := r.db.Exec(query, token.UserId, token.FamilyId, token.TokenHash, token.ExpiresAt)
+
+	return err
+}
+
+// RotateRefreshToken marks the token with the given hash as used and stores
+// next in the same family. Presenting a token that was already used revokes
+// its whole family, since that only happens when it was stolen and replayed.
+func (r *AuthPostgres) RotateRefreshToken(tokenHash string, next notes.RefreshToken) (notes.RefreshToken, error) {
+	var current notes.RefreshToken
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return next, err
+	}
+
+	selectQuery := fmt.Sprintf(
+		"SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at FROM %s WHERE token_hash=$1 FOR UPDATE",
+		refreshTokensTable,
+	)
+	if err := tx.Get(&current, selectQuery, tokenHash); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return next, notes.ErrInvalidToken
+		}
+		return next, err
+	}
+
+	if current.RevokedAt != nil {
+		tx.Rollback()
+		return next, notes.ErrInvalidToken
+	}
+
+	if current.UsedAt != nil {
+		revokeQuery := fmt.Sprintf("UPDATE %s SET revoked_at=now() WHERE family_id=$1 AND revoked_at IS NULL", refreshTokensTable)
+		if _, err := tx.Exec(revokeQuery, current.FamilyId); err != nil {
+			tx.Rollback()
+			return next, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return next, err
+		}
+
+		return next, notes.ErrTokenReused
+	}
+
+	if !current.ExpiresAt.After(time.Now()) {
+		tx.Rollback()
+		return next, notes.ErrInvalidToken
+	}
+
+	useQuery := fmt.Sprintf("UPDATE %s SET used_at=now() WHERE id=$1", refreshTokensTable)
+	if _, err := tx.Exec(useQuery, current.Id); err != nil {
+		tx.Rollback()
+		return next, err
+	}
+
+	next.UserId = current.UserId
+	next.FamilyId = current.FamilyId
+
+	createQuery := fmt.Sprintf("INSERT INTO %s (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)", refreshTokensTable)
+	if _, err := tx.Exec(createQuery, next.UserId, next.FamilyId, next.TokenHash, next.ExpiresAt); err != nil {
+		tx.Rollback()
+		return next, err
+	}
+
+	return next, tx.Commit()
+}
+
+func (r *AuthPostgres) RevokeRefreshTokenFamily(userId int, tokenHash string) error {
+	query := fmt.Sprintf(
+		"UPDATE %[1]s SET revoked_at=now() WHERE revoked_at IS NULL AND family_id=(SELECT family_id FROM %[1]s WHERE token_hash=$1 AND user_id=$2)",
+		refreshTokensTable,
+	)
+
+	_, err := r.db.Exec(query, tokenHash, userId)
+
+	return err
+}
+
+// RevokeToken adds an access token id to the revocation list. Entries are
+// only needed until the token would have expired anyway, so stale ones are
+// cleared on the way.
+func (r *AuthPostgres) RevokeToken(jti string, expiresAt time.Time) error {
+	cleanupQuery := fmt.Sprintf("DELETE FROM %s WHERE expires_at < now()", revokedTokensTable)
+	if _, err := r.db.Exec(cleanupQuery); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", revokedTokensTable)
+
+	_, err := r.db.Exec(query, jti, expiresAt)
+
+	return err
+}
+
+func (r *AuthPostgres) IsTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE jti=$1)", revokedTokensTable)
+
+	err := r.db.Get(&revoked, query, jti)
+
+	return revoked, err
+}
+
+func (r *AuthPostgres) CreatePersonalToken(token notes.PersonalAccessToken, tokenHash string) (int, error) {
+	var id int
+	query := fmt.Sprintf(
+		"INSERT INTO %s (user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		personalTokensTable,
+	)
+
+	row := r.db.QueryRow(query, token.UserId, token.Name, tokenHash, token.Scopes, token.ExpiresAt)
+	if err := row.Scan(&id); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (r *AuthPostgres) GetPersonalTokens(userId int) ([]notes.PersonalAccessToken, error) {
+	var tokens []notes.PersonalAccessToken
+	query := fmt.Sprintf(
+		"SELECT id, user_id, name, scopes, created_at, last_used_at, expires_at FROM %s WHERE user_id=$1 AND revoked_at IS NULL ORDER BY id",
+		personalTokensTable,
+	)
+
+	err := r.db.Select(&tokens, query, userId)
+
+	return tokens, err
+}
+
+func (r *AuthPostgres) RevokePersonalToken(userId, tokenId int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=now() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL", personalTokensTable)
+
+	res, err := r.db.Exec(query, tokenId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// UsePersonalToken looks up a live token by hash and stamps its last use in
+// the same statement.
+func (r *AuthPostgres) UsePersonalToken(tokenHash string) (notes.PersonalAccessToken, error) {
+	var token notes.PersonalAccessToken
+	query := fmt.Sprintf(
+		`UPDATE %s SET last_used_at=now() WHERE token_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+		RETURNING id, user_id, name, scopes, created_at, last_used_at, expires_at`,
+		personalTokensTable,
+	)
+
+	err := r.db.Get(&token, query, tokenHash)
+
+	return token, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Liopun/notes-app"
@@ -32,12 +33,13 @@ func TestAuthPostgres_CreateUse(t *testing.T) {
 			name: "OK",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO users").WithArgs("Test", "test", "password").WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO users").WithArgs("Test", "test", "password", "test@example.com").WillReturnRows(rows)
 			},
 			input: notes.User{
 				Name:     "Test",
 				Username: "test",
 				Password: "password",
+				Email:    "test@example.com",
 			},
 			want: 1,
 		},
@@ -45,7 +47,7 @@ func TestAuthPostgres_CreateUse(t *testing.T) {
 			name: "Empty Fields",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
-				mock.ExpectQuery("INSERT INTO users").WithArgs("Test", "test", "").WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO users").WithArgs("Test", "test", "", "").WillReturnRows(rows)
 			},
 			input: notes.User{
 				Name:     "Test",
@@ -87,7 +89,6 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 
 	type args struct {
 		username string
-		password string
 	}
 
 	tests := []struct {
@@ -101,9 +102,9 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 			name: "OK",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "name", "username", "password"}).AddRow(1, "Test", "test", "password")
-				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("test", "password").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("test").WillReturnRows(rows)
 			},
-			input: args{"test", "password"},
+			input: args{"test"},
 			want: notes.User{
 				Id:       1,
 				Name:     "Test",
@@ -115,9 +116,9 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 			name: "Not Found",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "name", "username", "password"})
-				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("not", "found").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("notfound").WillReturnRows(rows)
 			},
-			input:   args{"not", "found"},
+			input:   args{"notfound"},
 			wantErr: true,
 		},
 	}
@@ -126,7 +127,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetUser(tt.input.username, tt.input.password)
+			got, err := r.GetUser(tt.input.username)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -138,3 +139,237 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPostgres_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewAuthPostgres(sqlxDb)
+
+	mock.ExpectExec("UPDATE users SET hashed_password=(.+) WHERE id=(.+)").
+		WithArgs("$argon2id$hash", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.UpdatePasswordHash(1, "$argon2id$hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewAuthPostgres(sqlxDb)
+
+	columns := []string{"id", "user_id", "family_id", "token_hash", "expires_at", "used_at", "revoked_at"}
+	expiresAt := time.Now().Add(time.Hour)
+	next := notes.RefreshToken{TokenHash: "next", ExpiresAt: expiresAt}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    notes.RefreshToken
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "family", "current", expiresAt, nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash=(.+) FOR UPDATE").WithArgs("current").WillReturnRows(rows)
+				mock.ExpectExec("UPDATE refresh_tokens SET used_at=now()").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO refresh_tokens").WithArgs(2, "family", "next", expiresAt).WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectCommit()
+			},
+			want: notes.RefreshToken{UserId: 2, FamilyId: "family", TokenHash: "next", ExpiresAt: expiresAt},
+		},
+		{
+			name: "Unknown",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens").WithArgs("current").WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectRollback()
+			},
+			wantErr: notes.ErrInvalidToken,
+		},
+		{
+			name: "Expired",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "family", "current", time.Now().Add(-time.Hour), nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens").WithArgs("current").WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: notes.ErrInvalidToken,
+		},
+		{
+			name: "Reused",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "family", "current", expiresAt, time.Now(), nil)
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens").WithArgs("current").WillReturnRows(rows)
+				mock.ExpectExec("UPDATE refresh_tokens SET revoked_at=now\\(\\) WHERE family_id=(.+)").WithArgs("family").WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+			wantErr: notes.ErrTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RotateRefreshToken("current", next)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_RevokeToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewAuthPostgres(sqlxDb)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectExec("DELETE FROM revoked_tokens WHERE expires_at < now()").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO revoked_tokens").WithArgs("jti", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.RevokeToken("jti", expiresAt))
+
+	mock.ExpectQuery("SELECT EXISTS (.+) FROM revoked_tokens").WithArgs("jti").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	revoked, err := r.IsTokenRevoked("jti")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_UsePersonalToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewAuthPostgres(sqlxDb)
+
+	createdAt := time.Now()
+	columns := []string{"id", "user_id", "name", "scopes", "created_at", "last_used_at", "expires_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    notes.PersonalAccessToken
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "backup", "lists:read items:read", createdAt, createdAt, nil)
+				mock.ExpectQuery("UPDATE personal_access_tokens SET last_used_at=now\\(\\) WHERE token_hash=(.+) AND revoked_at IS NULL").
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			want: notes.PersonalAccessToken{
+				Id:         1,
+				UserId:     2,
+				Name:       "backup",
+				Scopes:     notes.Scopes{notes.ScopeListsRead, notes.ScopeItemsRead},
+				CreatedAt:  createdAt,
+				LastUsedAt: &createdAt,
+			},
+		},
+		{
+			name: "Revoked Or Expired",
+			mock: func() {
+				mock.ExpectQuery("UPDATE personal_access_tokens").WithArgs("hash").WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UsePersonalToken("hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_PersonalTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewAuthPostgres(sqlxDb)
+
+	token := notes.PersonalAccessToken{
+		UserId: 1,
+		Name:   "backup",
+		Scopes: notes.Scopes{notes.ScopeListsRead, notes.ScopeItemsWrite},
+	}
+
+	mock.ExpectQuery("INSERT INTO personal_access_tokens").
+		WithArgs(1, "backup", "hash", "lists:read items:write", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	id, err := r.CreatePersonalToken(token, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, id)
+
+	mock.ExpectExec("UPDATE personal_access_tokens SET revoked_at=now()").WithArgs(5, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.RevokePersonalToken(1, 5))
+
+	mock.ExpectExec("UPDATE personal_access_tokens SET revoked_at=now()").WithArgs(5, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Error(t, r.RevokePersonalToken(2, 5))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+type ChecklistPostgres struct {
+	db *sqlx.DB
+}
+
+func NewChecklistPostgres(db *sqlx.DB) *ChecklistPostgres {
+	return &ChecklistPostgres{db: db}
+}
+
+// touchItemQuery marks item $1 as modified, so that incremental syncs of
+// its list pick up checklist changes. It also locks the item, which
+// serializes changes to its checklist.
+var touchItemQuery = fmt.Sprintf("UPDATE %s SET updated_at=now() WHERE id = $1", notesItemsTable)
+
+// Create adds the entry at the end of the item's checklist.
+func (r *ChecklistPostgres) Create(itemId int, entry notes.ChecklistEntry) (int, error) {
+	var (
+		entryId int
+		last    string
+	)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return -1, err
+	}
+
+	if _, err := tx.Exec(touchItemQuery, itemId); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	lastQuery := fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE item_id = $1", checklistEntriesTable)
+	if err := tx.QueryRow(lastQuery, itemId).Scan(&last); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	createQuery := fmt.Sprintf("INSERT INTO %s (item_id, text, checked, position) values ($1, $2, $3, $4) RETURNING id", checklistEntriesTable)
+	if err := tx.QueryRow(createQuery, itemId, entry.Text, entry.Checked, rankAfter(last)).Scan(&entryId); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	return entryId, tx.Commit()
+}
+
+func (r *ChecklistPostgres) GetAll(itemId int) ([]notes.ChecklistEntry, error) {
+	var entries []notes.ChecklistEntry
+
+	query := fmt.Sprintf(
+		"SELECT id, text, checked, position, created_at, updated_at FROM %s WHERE item_id = $1 ORDER BY position, id",
+		checklistEntriesTable,
+	)
+	err := r.db.Select(&entries, query, itemId)
+
+	return entries, err
+}
+
+// Update changes an entry of the item; sql.ErrNoRows is returned when the
+// item has no such entry.
+func (r *ChecklistPostgres) Update(itemId, entryId int, inp notes.UpdateChecklistEntryInput) error {
+	qValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if inp.Text != nil {
+		qValues = append(qValues, fmt.Sprintf("text=$%d", argId))
+		args = append(args, *inp.Text)
+		argId++
+	}
+
+	if inp.Checked != nil {
+		qValues = append(qValues, fmt.Sprintf("checked=$%d", argId))
+		args = append(args, *inp.Checked)
+		argId++
+	}
+
+	qValues = append(qValues, "updated_at=now()")
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE item_id = $%d AND id = $%d",
+		checklistEntriesTable,
+		strings.Join(qValues, ", "),
+		argId,
+		argId+1,
+	)
+	args = append(args, itemId, entryId)
+
+	return r.change(itemId, query, args...)
+}
+
+func (r *ChecklistPostgres) Delete(itemId, entryId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE item_id = $1 AND id = $2", checklistEntriesTable)
+
+	return r.change(itemId, query, itemId, entryId)
+}
+
+// change runs a query changing one entry of the item and marks the item as
+// modified, in one transaction.
+func (r *ChecklistPostgres) change(itemId int, query string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(touchItemQuery, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := expectAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reorder places the entry directly after or before another entry of the
+// same checklist.
+func (r *ChecklistPostgres) Reorder(itemId, entryId int, inp notes.ReorderInput) error {
+	var (
+		anchor   string
+		neighbor string
+	)
+
+	anchorId, agg, cmp := inp.BeforeId, "max", "<"
+	if inp.AfterId != nil {
+		anchorId, agg, cmp = inp.AfterId, "min", ">"
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(touchItemQuery, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	anchorQuery := fmt.Sprintf(
+		"SELECT a.position FROM %[1]s a INNER JOIN %[1]s e on e.item_id = a.item_id WHERE a.item_id = $1 AND a.id = $2 AND e.id = $3 AND a.id <> $3",
+		checklistEntriesTable,
+	)
+	if err := tx.QueryRow(anchorQuery, itemId, *anchorId, entryId).Scan(&anchor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	neighborQuery := fmt.Sprintf(
+		"SELECT COALESCE(%s(position), '') FROM %s WHERE item_id = $1 AND id <> $2 AND position %s $3",
+		agg,
+		checklistEntriesTable,
+		cmp,
+	)
+	if err := tx.QueryRow(neighborQuery, itemId, entryId, anchor).Scan(&neighbor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position := rankBetween(neighbor, anchor)
+	if inp.AfterId != nil {
+		position = rankBetween(anchor, neighbor)
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET position=$1, updated_at=now() WHERE id = $2", checklistEntriesTable)
+	if _, err := tx.Exec(updateQuery, position, entryId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// copyChecklist duplicates the checklist of item from onto item to inside
+// tx. Unless keepChecked is set, the copies start out unchecked.
+func copyChecklist(tx *sql.Tx, from, to int, keepChecked bool) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %[1]s (item_id, text, checked, position) SELECT $2, text, checked AND $3, position FROM %[1]s WHERE item_id = $1",
+		checklistEntriesTable,
+	)
+	_, err := tx.Exec(query, from, to, keepChecked)
+
+	return err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewChecklistPostgres(sqlxDb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notes_items SET updated_at=now\\(\\) WHERE id = \\$1").
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM checklist_entries WHERE item_id = \\$1").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("i"))
+	mock.ExpectQuery("INSERT INTO checklist_entries \\(item_id, text, checked, position\\) values \\(\\$1, \\$2, \\$3, \\$4\\) RETURNING id").
+		WithArgs(4, "buy milk", false, "j").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	got, err := r.Create(4, notes.ChecklistEntry{Text: "buy milk"})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChecklistPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewChecklistPostgres(sqlxDb)
+
+	text, checked := "buy oat milk", true
+
+	tests := []struct {
+		name    string
+		input   notes.UpdateChecklistEntryInput
+		mock    func()
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: notes.UpdateChecklistEntryInput{Text: &text, Checked: &checked},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE notes_items SET updated_at=now\\(\\)").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE checklist_entries SET text=\\$1, checked=\\$2, updated_at=now\\(\\) WHERE item_id = \\$3 AND id = \\$4").
+					WithArgs("buy oat milk", true, 4, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Other Item",
+			input: notes.UpdateChecklistEntryInput{Checked: &checked},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE notes_items SET updated_at=now\\(\\)").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE checklist_entries SET checked=\\$1, updated_at=now\\(\\) WHERE item_id = \\$2 AND id = \\$3").
+					WithArgs(true, 4, 7).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Update(4, 7, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestChecklistPostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewChecklistPostgres(sqlxDb)
+
+	anchorId := 8
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notes_items SET updated_at=now\\(\\)").
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT a.position FROM checklist_entries a INNER JOIN checklist_entries e on e.item_id = a.item_id WHERE a.item_id = \\$1 AND a.id = \\$2 AND e.id = \\$3 AND a.id <> \\$3").
+		WithArgs(4, 8, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a"))
+	mock.ExpectQuery("SELECT COALESCE\\(min\\(position\\), ''\\) FROM checklist_entries WHERE item_id = \\$1 AND id <> \\$2 AND position > \\$3").
+		WithArgs(4, 7, "a").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("c"))
+	mock.ExpectExec("UPDATE checklist_entries SET position=\\$1, updated_at=now\\(\\) WHERE id = \\$2").
+		WithArgs("b", 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Reorder(4, 7, notes.ReorderInput{AfterId: &anchorId}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notes_items SET updated_at=now\\(\\)").
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT a.position FROM checklist_entries").
+		WithArgs(4, 8, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.Reorder(4, 7, notes.ReorderInput{BeforeId: &anchorId}), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+type FolderPostgres struct {
+	db *sqlx.DB
+}
+
+func NewFolderPostgres(db *sqlx.DB) *FolderPostgres {
+	return &FolderPostgres{db: db}
+}
+
+// Create adds a folder under one of the user's folders, or at the top
+// level without a parent. sql.ErrNoRows is returned when the parent isn't
+// the user's.
+func (r *FolderPostgres) Create(userId int, folder notes.Folder) (int, error) {
+	var id int
+
+	query := fmt.Sprintf(
+		`INSERT INTO %[1]s (user_id, parent_id, name) SELECT $1, $2, $3
+		WHERE $2::int IS NULL OR EXISTS (SELECT 1 FROM %[1]s WHERE id = $2 AND user_id = $1) RETURNING id`,
+		foldersTable,
+	)
+	err := r.db.QueryRow(query, userId, folder.ParentId, folder.Name).Scan(&id)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (r *FolderPostgres) Update(userId, folderId int, inp notes.UpdateFolderInput) error {
+	query := fmt.Sprintf("UPDATE %s SET name=$1 WHERE id = $2 AND user_id = $3", foldersTable)
+
+	res, err := r.db.Exec(query, *inp.Name, folderId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// Delete removes the folder and its subfolders. The lists in them are
+// kept, at the top level.
+func (r *FolderPostgres) Delete(userId, folderId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", foldersTable)
+
+	res, err := r.db.Exec(query, folderId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// Move puts the folder under another of the user's folders, or at the top
+// level when parentId is nil. notes.ErrFolderCycle is returned when the
+// new parent is the folder itself or inside it. The user's folders are
+// locked while moving, so that two concurrent moves can't form a cycle
+// together.
+func (r *FolderPostgres) Move(userId, folderId int, parentId *int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 FOR UPDATE", foldersTable)
+	if _, err := tx.Exec(lockQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if parentId != nil {
+		var cycle, found int
+
+		ancestorsQuery := fmt.Sprintf(
+			`WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM %[1]s WHERE id = $1 AND user_id = $2
+				UNION ALL
+				SELECT f.id, f.parent_id FROM %[1]s f INNER JOIN ancestors a on f.id = a.parent_id
+			)
+			SELECT count(*) FILTER (WHERE id = $3), count(*) FROM ancestors`,
+			foldersTable,
+		)
+		if err := tx.QueryRow(ancestorsQuery, *parentId, userId, folderId).Scan(&cycle, &found); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if found == 0 {
+			tx.Rollback()
+			return sql.ErrNoRows
+		}
+
+		if cycle > 0 {
+			tx.Rollback()
+			return notes.ErrFolderCycle
+		}
+	}
+
+	moveQuery := fmt.Sprintf("UPDATE %s SET parent_id=$1 WHERE id = $2 AND user_id = $3", foldersTable)
+	res, err := tx.Exec(moveQuery, parentId, folderId, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := expectAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PlaceList keeps a list the user is a member of in one of their folders,
+// or in none when folderId is nil.
+func (r *FolderPostgres) PlaceList(userId, listId int, folderId *int) error {
+	query := fmt.Sprintf(
+		`UPDATE %s ul SET folder_id=$3 FROM %s tl WHERE tl.id = ul.list_id AND ul.user_id = $1 AND ul.list_id = $2 AND tl.deleted_at IS NULL
+		AND ($3::int IS NULL OR EXISTS (SELECT 1 FROM %s WHERE id = $3 AND user_id = $1))`,
+		usersListsTable,
+		notesListsTable,
+		foldersTable,
+	)
+
+	res, err := r.db.Exec(query, userId, listId, folderId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// treeRow is a folder or a list of the user's tree. ParentId is the
+// parent of a folder and the folder of a list.
+type treeRow struct {
+	Kind string `db:"kind"`
+	notes.NotesList
+	ParentId *int `db:"parent_id"`
+
+	// Only used for ordering.
+	Depth   int    `db:"depth"`
+	SortKey string `db:"sort_key"`
+}
+
+// GetTree returns all of the user's folders and the lists they are a
+// member of in one query: folders are walked from the top level down,
+// parents before their subfolders, and ordered by name within a level;
+// lists keep the user's order.
+func (r *FolderPostgres) GetTree(userId int) ([]notes.Folder, []notes.FolderedList, error) {
+	var rows []treeRow
+
+	query := fmt.Sprintf(
+		`WITH RECURSIVE tree AS (
+			SELECT id, parent_id, name, created_at, 1 AS depth FROM %[1]s WHERE user_id = $1 AND parent_id IS NULL
+			UNION ALL
+			SELECT f.id, f.parent_id, f.name, f.created_at, t.depth + 1 FROM %[1]s f INNER JOIN tree t on f.parent_id = t.id
+		)
+		SELECT 'folder' AS kind, id, parent_id, name AS title, '' AS description, '' AS role, '' AS position, created_at, created_at AS updated_at, 0 AS version,
+			depth, lower(name) COLLATE "C" AS sort_key FROM tree
+		UNION ALL
+		SELECT 'list', tl.id, ul.folder_id, tl.title, tl.description, ul.role, ul.position, tl.created_at, tl.updated_at, tl.version,
+			0, ul.position COLLATE "C" FROM %[2]s ul INNER JOIN %[3]s tl on tl.id = ul.list_id WHERE ul.user_id = $1 AND tl.deleted_at IS NULL
+		ORDER BY kind, depth, sort_key, id`,
+		foldersTable,
+		usersListsTable,
+		notesListsTable,
+	)
+	if err := r.db.Select(&rows, query, userId); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		folders []notes.Folder
+		lists   []notes.FolderedList
+	)
+	for _, row := range rows {
+		if row.Kind == "folder" {
+			folders = append(folders, notes.Folder{Id: row.Id, ParentId: row.ParentId, Name: row.Title, CreatedAt: row.CreatedAt})
+			continue
+		}
+
+		lists = append(lists, notes.FolderedList{NotesList: row.NotesList, FolderId: row.ParentId})
+	}
+
+	return folders, lists, nil
+}
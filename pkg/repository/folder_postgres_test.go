@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewFolderPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		input   notes.Folder
+		mock    func()
+		want    int
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: notes.Folder{Name: "Work"},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO folders \\(user_id, parent_id, name\\) SELECT \\$1, \\$2, \\$3 WHERE \\$2::int IS NULL OR EXISTS").
+					WithArgs(1, nil, "Work").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			want: 2,
+		},
+		{
+			name:  "Parent Not Found",
+			input: notes.Folder{ParentId: intPointer(9), Name: "Project X"},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO folders").
+					WithArgs(1, 9, "Project X").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			want:    -1,
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Create(1, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFolderPostgres_Move(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewFolderPostgres(sqlxDb)
+
+	tests := []struct {
+		name     string
+		parentId *int
+		mock     func()
+		wantErr  error
+	}{
+		{
+			name:     "OK",
+			parentId: intPointer(3),
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM folders WHERE user_id = \\$1 FOR UPDATE").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectQuery("WITH RECURSIVE ancestors AS \\( SELECT id, parent_id FROM folders WHERE id = \\$1 AND user_id = \\$2").
+					WithArgs(3, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"cycle", "found"}).AddRow(0, 2))
+				mock.ExpectExec("UPDATE folders SET parent_id=\\$1 WHERE id = \\$2 AND user_id = \\$3").
+					WithArgs(3, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "To Top Level",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM folders").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec("UPDATE folders SET parent_id=\\$1").
+					WithArgs(nil, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "Into Subfolder",
+			parentId: intPointer(5),
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM folders").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectQuery("WITH RECURSIVE ancestors").
+					WithArgs(5, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"cycle", "found"}).AddRow(1, 3))
+				mock.ExpectRollback()
+			},
+			wantErr: notes.ErrFolderCycle,
+		},
+		{
+			name:     "Parent Not Found",
+			parentId: intPointer(9),
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM folders").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectQuery("WITH RECURSIVE ancestors").
+					WithArgs(9, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"cycle", "found"}).AddRow(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "Folder Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM folders").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE folders SET parent_id=\\$1").
+					WithArgs(nil, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Move(1, 2, tt.parentId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFolderPostgres_GetTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewFolderPostgres(sqlxDb)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"kind", "id", "parent_id", "title", "description", "role", "position", "created_at", "updated_at", "version", "depth", "sort_key"}
+
+	mock.ExpectQuery("WITH RECURSIVE tree AS \\(.*\\) SELECT 'folder' AS kind.* UNION ALL SELECT 'list'.* ORDER BY kind, depth, sort_key, id").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("folder", 2, nil, "Work", "", "", "", created, created, 0, 1, "work").
+			AddRow("folder", 3, 2, "Project X", "", "", "", created, created, 0, 2, "project x").
+			AddRow("list", 4, 3, "backlog", "sprint", notes.RoleOwner, "n", created, created, 5, 0, "n").
+			AddRow("list", 5, nil, "groceries", "", notes.RoleEditor, "t", created, created, 1, 0, "t"))
+
+	folders, lists, err := r.GetTree(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.Folder{
+		{Id: 2, Name: "Work", CreatedAt: created},
+		{Id: 3, ParentId: intPointer(2), Name: "Project X", CreatedAt: created},
+	}, folders)
+	assert.Equal(t, []notes.FolderedList{
+		{
+			NotesList: notes.NotesList{Id: 4, Title: "backlog", Description: "sprint", Role: notes.RoleOwner, Position: "n", CreatedAt: created, UpdatedAt: created, Version: 5},
+			FolderId:  intPointer(3),
+		},
+		{
+			NotesList: notes.NotesList{Id: 5, Title: "groceries", Role: notes.RoleEditor, Position: "t", CreatedAt: created, UpdatedAt: created, Version: 1},
+		},
+	}, lists)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func intPointer(i int) *int {
+	return &i
+}
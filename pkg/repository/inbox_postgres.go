@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+type InboxPostgres struct {
+	db *sqlx.DB
+}
+
+func NewInboxPostgres(db *sqlx.DB) *InboxPostgres {
+	return &InboxPostgres{db: db}
+}
+
+// Create puts the message into the user's inbox. Messages are keyed by the
+// reminder delivery they come from, so that retrying a delivery whose
+// outcome was lost doesn't post it twice.
+func (r *InboxPostgres) Create(deliveryId int, msg notes.InboxMessage) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (user_id, item_id, delivery_id, title, body) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (delivery_id) DO NOTHING",
+		inboxMessagesTable,
+	)
+	_, err := r.db.Exec(query, msg.UserId, msg.ItemId, deliveryId, msg.Title, msg.Body)
+
+	return err
+}
+
+// GetAll returns the user's messages, newest first.
+func (r *InboxPostgres) GetAll(userId int) ([]notes.InboxMessage, error) {
+	var messages []notes.InboxMessage
+
+	query := fmt.Sprintf(
+		"SELECT id, item_id, title, body, created_at, read_at FROM %s WHERE user_id = $1 ORDER BY created_at DESC, id DESC",
+		inboxMessagesTable,
+	)
+	err := r.db.Select(&messages, query, userId)
+
+	return messages, err
+}
+
+// MarkRead marks the message as read; reading it again keeps the time it
+// was first read.
+func (r *InboxPostgres) MarkRead(userId, messageId int) error {
+	query := fmt.Sprintf("UPDATE %s SET read_at=COALESCE(read_at, now()) WHERE user_id = $1 AND id = $2", inboxMessagesTable)
+
+	res, err := r.db.Exec(query, userId, messageId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInboxPostgres(sqlxDb)
+
+	itemId := 5
+
+	mock.ExpectExec("INSERT INTO inbox_messages \\(user_id, item_id, delivery_id, title, body\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) ON CONFLICT \\(delivery_id\\) DO NOTHING").
+		WithArgs(1, 5, 3, "title", "body").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.Create(3, notes.InboxMessage{UserId: 1, ItemId: &itemId, Title: "title", Body: "body"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxPostgres_MarkRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInboxPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE inbox_messages SET read_at=COALESCE\\(read_at, now\\(\\)\\) WHERE user_id = \\$1 AND id = \\$2").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE inbox_messages").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.MarkRead(1, 2)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
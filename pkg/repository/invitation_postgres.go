@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+type InvitationPostgres struct {
+	db *sqlx.DB
+}
+
+func NewInvitationPostgres(db *sqlx.DB) *InvitationPostgres {
+	return &InvitationPostgres{db: db}
+}
+
+// selectInvitationsQuery resolves the list title and both usernames of an
+// invitation; callers append the WHERE clause.
+var selectInvitationsQuery = fmt.Sprintf(
+	`SELECT i.id, i.list_id, tl.title AS list_title, iu.username AS inviter, u.username AS invitee, i.role, i.status, i.created_at, i.expires_at
+	FROM %s i INNER JOIN %s tl on tl.id = i.list_id INNER JOIN %s iu on iu.id = i.inviter_id LEFT JOIN %s u on u.id = i.invitee_id`,
+	listInvitationsTable,
+	notesListsTable,
+	usersTable,
+	usersTable,
+)
+
+// Create stores a pending invitation. When inv.Invitee is set it must name
+// an existing user other than the inviter, otherwise sql.ErrNoRows is
+// returned; without an invitee the invitation is a link invitation.
+func (r *InvitationPostgres) Create(inviterId int, inv notes.Invitation) (int, error) {
+	var id int
+
+	if inv.Invitee == nil {
+		query := fmt.Sprintf("INSERT INTO %s (list_id, inviter_id, role, expires_at) VALUES ($1, $2, $3, $4) RETURNING id", listInvitationsTable)
+		row := r.db.QueryRow(query, inv.ListId, inviterId, inv.Role, inv.ExpiresAt)
+		err := row.Scan(&id)
+
+		return id, err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (list_id, inviter_id, invitee_id, role, expires_at)
+		SELECT $1, $2, u.id, $3, $4 FROM %s u WHERE u.username = $5 AND u.id <> $2 RETURNING id`,
+		listInvitationsTable,
+		usersTable,
+	)
+	row := r.db.QueryRow(query, inv.ListId, inviterId, inv.Role, inv.ExpiresAt, *inv.Invitee)
+	err := row.Scan(&id)
+
+	return id, err
+}
+
+// GetPending returns the open invitations addressed to the user, leaving
+// out those to lists in the trash.
+func (r *InvitationPostgres) GetPending(userId int) ([]notes.Invitation, error) {
+	var invitations []notes.Invitation
+
+	query := fmt.Sprintf(
+		"%s WHERE i.invitee_id = $1 AND i.status = '%s' AND i.expires_at > now() AND tl.deleted_at IS NULL ORDER BY i.created_at DESC",
+		selectInvitationsQuery,
+		notes.InvitationPending,
+	)
+	err := r.db.Select(&invitations, query, userId)
+
+	return invitations, err
+}
+
+// GetByList returns the open invitations of a list, link invitations
+// included.
+func (r *InvitationPostgres) GetByList(listId int) ([]notes.Invitation, error) {
+	var invitations []notes.Invitation
+
+	query := fmt.Sprintf(
+		"%s WHERE i.list_id = $1 AND i.status = '%s' AND i.expires_at > now() ORDER BY i.created_at DESC",
+		selectInvitationsQuery,
+		notes.InvitationPending,
+	)
+	err := r.db.Select(&invitations, query, listId)
+
+	return invitations, err
+}
+
+// Accept accepts an invitation addressed to the user. Link invitations and
+// invitations addressed to someone else are reported as sql.ErrNoRows:
+// link invitations can only be redeemed with their token, see AcceptLink.
+func (r *InvitationPostgres) Accept(userId, invitationId int) (int, error) {
+	return r.accept(userId, invitationId, false)
+}
+
+// AcceptLink accepts a link invitation for the user, who is expected to
+// have presented its signed token. Invitations addressed to a user are
+// reported as sql.ErrNoRows.
+func (r *InvitationPostgres) AcceptLink(userId, invitationId int) (int, error) {
+	return r.accept(userId, invitationId, true)
+}
+
+// accept marks the invitation accepted and makes the user a member of its
+// list in one transaction, returning the list id. A user who is already a
+// member keeps the higher of the two roles. Invitations that are no longer
+// pending or have expired are reported as notes.ErrInvitationClosed.
+func (r *InvitationPostgres) accept(userId, invitationId int, link bool) (int, error) {
+	var (
+		listId    int
+		inviteeId sql.NullInt64
+		role      string
+		status    string
+		expiresAt time.Time
+	)
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return -1, err
+	}
+
+	selectQuery := fmt.Sprintf("SELECT list_id, invitee_id, role, status, expires_at FROM %s WHERE id=$1 FOR UPDATE", listInvitationsTable)
+	row := tx.QueryRow(selectQuery, invitationId)
+	if err := row.Scan(&listId, &inviteeId, &role, &status, &expiresAt); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	addressed := inviteeId.Valid && int(inviteeId.Int64) == userId
+	if link && inviteeId.Valid || !link && !addressed {
+		tx.Rollback()
+		return -1, sql.ErrNoRows
+	}
+
+	if status != notes.InvitationPending || !expiresAt.After(time.Now()) {
+		tx.Rollback()
+		return -1, notes.ErrInvitationClosed
+	}
+
+	acceptQuery := fmt.Sprintf("UPDATE %s SET status='%s', invitee_id=$1 WHERE id=$2", listInvitationsTable, notes.InvitationAccepted)
+	if _, err := tx.Exec(acceptQuery, userId, invitationId); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	var last string
+	if err := tx.QueryRow(lastListPositionQuery, userId).Scan(&last); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	memberQuery := fmt.Sprintf(
+		`INSERT INTO %[1]s (user_id, list_id, role, position) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, list_id) DO UPDATE SET role = EXCLUDED.role
+		WHERE array_position(%[2]s, %[1]s.role) > array_position(%[2]s, EXCLUDED.role)`,
+		usersListsTable,
+		roleOrder,
+	)
+	if _, err := tx.Exec(memberQuery, userId, listId, role, rankAfter(last)); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	return listId, tx.Commit()
+}
+
+func (r *InvitationPostgres) Decline(userId, invitationId int) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status='%s' WHERE id=$1 AND invitee_id=$2 AND status='%s'",
+		listInvitationsTable,
+		notes.InvitationDeclined,
+		notes.InvitationPending,
+	)
+
+	res, err := r.db.Exec(query, invitationId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+func (r *InvitationPostgres) Revoke(listId, invitationId int) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status='%s' WHERE id=$1 AND list_id=$2 AND status='%s'",
+		listInvitationsTable,
+		notes.InvitationRevoked,
+		notes.InvitationPending,
+	)
+
+	res, err := r.db.Exec(query, invitationId, listId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// ExpirePending moves every pending invitation past its expiry to the
+// expired state and returns how many there were.
+func (r *InvitationPostgres) ExpirePending() (int64, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status='%s' WHERE status='%s' AND expires_at <= now()",
+		listInvitationsTable,
+		notes.InvitationExpired,
+		notes.InvitationPending,
+	)
+
+	res, err := r.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInvitationPostgres(sqlxDb)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		input   notes.Invitation
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "By Username",
+			input: notes.Invitation{ListId: 2, Invitee: stringPointer("bob"), Role: notes.RoleEditor, ExpiresAt: expiresAt},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(5)
+				mock.ExpectQuery("INSERT INTO list_invitations (.+) SELECT (.+) FROM users u WHERE u.username = (.+) RETURNING id").
+					WithArgs(2, 1, notes.RoleEditor, expiresAt, "bob").
+					WillReturnRows(rows)
+			},
+			want: 5,
+		},
+		{
+			name:  "Link",
+			input: notes.Invitation{ListId: 2, Role: notes.RoleViewer, ExpiresAt: expiresAt},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(6)
+				mock.ExpectQuery("INSERT INTO list_invitations (.+) VALUES").
+					WithArgs(2, 1, notes.RoleViewer, expiresAt).
+					WillReturnRows(rows)
+			},
+			want: 6,
+		},
+		{
+			name:  "Unknown User",
+			input: notes.Invitation{ListId: 2, Invitee: stringPointer("nobody"), Role: notes.RoleEditor, ExpiresAt: expiresAt},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+				mock.ExpectQuery("INSERT INTO list_invitations").
+					WithArgs(2, 1, notes.RoleEditor, expiresAt, "nobody").
+					WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Create(1, tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationPostgres_Accept(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInvitationPostgres(sqlxDb)
+
+	columns := []string{"list_id", "invitee_id", "role", "status", "expires_at"}
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations WHERE id=(.+) FOR UPDATE").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, notes.RoleEditor, notes.InvitationPending, future))
+				mock.ExpectExec("UPDATE list_invitations SET status='accepted'").
+					WithArgs(1, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM users_lists WHERE user_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("i0001"))
+				mock.ExpectExec("INSERT INTO users_lists (.+) ON CONFLICT (.+) DO UPDATE SET role").
+					WithArgs(1, 2, notes.RoleEditor, "i0002").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "Link",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, nil, notes.RoleOwner, notes.InvitationPending, future))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "Other Invitee",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 3, notes.RoleEditor, notes.InvitationPending, future))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "Declined",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, notes.RoleEditor, notes.InvitationDeclined, future))
+				mock.ExpectRollback()
+			},
+			wantErr: notes.ErrInvitationClosed,
+		},
+		{
+			name: "Expired",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, notes.RoleEditor, notes.InvitationPending, time.Now().Add(-time.Minute)))
+				mock.ExpectRollback()
+			},
+			wantErr: notes.ErrInvitationClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Accept(1, 5)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationPostgres_AcceptLink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInvitationPostgres(sqlxDb)
+
+	columns := []string{"list_id", "invitee_id", "role", "status", "expires_at"}
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations WHERE id=(.+) FOR UPDATE").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, nil, notes.RoleViewer, notes.InvitationPending, future))
+				mock.ExpectExec("UPDATE list_invitations SET status='accepted'").
+					WithArgs(1, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM users_lists WHERE user_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("i0001"))
+				mock.ExpectExec("INSERT INTO users_lists").
+					WithArgs(1, 2, notes.RoleViewer, "i0002").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "Addressed",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM list_invitations").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, notes.RoleEditor, notes.InvitationPending, future))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.AcceptLink(1, 5)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationPostgres_Decline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInvitationPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE list_invitations SET status='declined' WHERE (.+) AND status='pending'").
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Pending",
+			mock: func() {
+				mock.ExpectExec("UPDATE list_invitations").
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Decline(1, 5)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationPostgres_ExpirePending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewInvitationPostgres(sqlxDb)
+
+	mock.ExpectExec("UPDATE list_invitations SET status='expired' WHERE status='pending' AND expires_at <= now()").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	got, err := r.ExpirePending()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type LinkPostgres struct {
+	db *sqlx.DB
+}
+
+func NewLinkPostgres(db *sqlx.DB) *LinkPostgres {
+	return &LinkPostgres{db: db}
+}
+
+// linkColumns and linkTargetJoin select links il together with their
+// targets as seen by user $1 and the reason a link is broken, if it is.
+// Targets the user can't read are reported as missing, so that links don't
+// reveal items of other users' lists.
+var (
+	linkColumns = fmt.Sprintf(
+		`il.source_id, il.ref, t.id AS target_id, t.title,
+		CASE WHEN t.id IS NULL THEN '%s' WHEN t.deleted THEN '%s' WHEN il.ref NOT LIKE '#%%' AND lower(t.title) <> lower(il.ref) THEN '%s' ELSE '' END AS broken`,
+		notes.LinkMissing,
+		notes.LinkDeleted,
+		notes.LinkRenamed,
+	)
+	linkTargetJoin = fmt.Sprintf(
+		`LEFT JOIN LATERAL (
+			SELECT ti.id, ti.title, ti.deleted_at IS NOT NULL OR tl.deleted_at IS NOT NULL AS deleted
+			FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+			WHERE ti.id = il.target_id AND ul.user_id = $1 LIMIT 1
+		) t on true`,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+	)
+)
+
+// GetLinks returns the links in the description of an item.
+func (r *LinkPostgres) GetLinks(userId, itemId int) ([]notes.ItemLink, error) {
+	var links []notes.ItemLink
+
+	query := fmt.Sprintf("SELECT %s FROM %s il %s WHERE il.source_id = $2 ORDER BY il.ref", linkColumns, itemLinksTable, linkTargetJoin)
+	err := r.db.Select(&links, query, userId, itemId)
+
+	return links, err
+}
+
+// GetBacklinks returns the items of the user's lists that link to an item,
+// most recently modified first.
+func (r *LinkPostgres) GetBacklinks(userId, itemId int) ([]notes.ListedItem, error) {
+	var items []notes.ListedItem
+
+	query := fmt.Sprintf(
+		`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE ul.user_id = $1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM %s il WHERE il.source_id = ti.id AND il.target_id = $2)
+		ORDER BY ti.updated_at DESC, ti.id`,
+		itemColumns,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+		itemLinksTable,
+	)
+	err := r.db.Select(&items, query, userId, itemId)
+
+	return items, err
+}
+
+// GetBroken returns the broken links in all items of the user's lists.
+func (r *LinkPostgres) GetBroken(userId int) ([]notes.BrokenLink, error) {
+	var links []notes.BrokenLink
+
+	query := fmt.Sprintf(
+		`SELECT * FROM (
+			SELECT %s, s.title AS source_title, sl.list_id FROM %s il
+			INNER JOIN %s s on s.id = il.source_id INNER JOIN %s sl on sl.item_id = s.id INNER JOIN %s su on su.list_id = sl.list_id INNER JOIN %s stl on stl.id = sl.list_id
+			%s
+			WHERE su.user_id = $1 AND s.deleted_at IS NULL AND stl.deleted_at IS NULL
+		) links WHERE broken <> '' ORDER BY list_id, source_id, ref`,
+		linkColumns,
+		itemLinksTable,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+		linkTargetJoin,
+	)
+	err := r.db.Select(&links, query, userId)
+
+	return links, err
+}
+
+// setLinks replaces the links of item itemId with those in description,
+// inside tx. Links are resolved among the items of userId's lists: an
+// "#id" link to that item, a title link to the item of that title, in the
+// same list if there is one and otherwise the oldest. Links that can't be
+// resolved are kept without a target.
+func setLinks(tx *sql.Tx, userId, itemId int, description string) error {
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE source_id = $1", itemLinksTable)
+	if _, err := tx.Exec(deleteQuery, itemId); err != nil {
+		return err
+	}
+
+	refs := notes.ParseLinks(description)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO %[1]s (source_id, ref, target_id)
+		SELECT $1, r.ref, (
+			SELECT ti.id FROM %[2]s ti INNER JOIN %[3]s li on li.item_id = ti.id INNER JOIN %[4]s ul on ul.list_id = li.list_id INNER JOIN %[5]s tl on tl.id = li.list_id
+			WHERE ul.user_id = $2 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+			AND CASE WHEN r.ref LIKE '#%%' THEN ti.id = substr(r.ref, 2)::int ELSE lower(ti.title) = lower(r.ref) END
+			ORDER BY li.list_id = (SELECT list_id FROM %[3]s WHERE item_id = $1) DESC, ti.id LIMIT 1
+		) FROM unnest($3::varchar[]) AS r(ref)`,
+		itemLinksTable,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+	)
+	_, err := tx.Exec(insertQuery, itemId, userId, pq.Array(refs))
+
+	return err
+}
+
+// copyLinks gives item to the links of item from, inside tx.
+func copyLinks(tx *sql.Tx, from, to int) error {
+	query := fmt.Sprintf("INSERT INTO %[1]s (source_id, ref, target_id) SELECT $2, ref, target_id FROM %[1]s WHERE source_id = $1", itemLinksTable)
+	_, err := tx.Exec(query, from, to)
+
+	return err
+}
+
+// rewriteLinks points the title links to item itemId at its new title,
+// inside tx. Only items userId can edit are changed; each change is
+// recorded as a revision authored by them.
+func rewriteLinks(tx *sqlx.Tx, userId, itemId int, title string) error {
+	var sources []struct {
+		Id          int    `db:"id"`
+		Description string `db:"description"`
+		Ref         string `db:"ref"`
+	}
+
+	sourcesQuery := fmt.Sprintf(
+		`SELECT ti.id, ti.description, il.ref FROM %s il INNER JOIN %s ti on ti.id = il.source_id INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE il.target_id = $1 AND il.ref NOT LIKE '#%%' AND ti.id <> $1 AND ul.user_id = $2 AND ul.role IN (%s) AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+		ORDER BY ti.id FOR UPDATE OF ti`,
+		itemLinksTable,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+		writeRoles,
+	)
+	if err := tx.Select(&sources, sourcesQuery, itemId, userId); err != nil {
+		return err
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET description=$1, updated_at=now(), version=version+1 WHERE id = $2", notesItemsTable)
+	// A link that already reads like the new title now means this item.
+	dropQuery := fmt.Sprintf("DELETE FROM %s WHERE source_id = $1 AND lower(ref) = lower($3) AND ref <> $2", itemLinksTable)
+	renameQuery := fmt.Sprintf("UPDATE %s SET ref=$3 WHERE source_id = $1 AND ref = $2", itemLinksTable)
+
+	for _, s := range sources {
+		description := notes.RewriteLinks(s.Description, s.Ref, title)
+		if description == s.Description {
+			continue
+		}
+
+		if _, err := tx.Exec(updateQuery, description, s.Id); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(createRevisionQuery, s.Id, userId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(dropQuery, s.Id, s.Ref, title); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(renameQuery, s.Id, s.Ref, title); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
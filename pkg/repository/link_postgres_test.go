@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkPostgres_GetLinks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewLinkPostgres(sqlxDb)
+
+	mock.ExpectQuery("SELECT il.source_id, il.ref, t.id AS target_id, t.title, CASE WHEN t.id IS NULL THEN 'missing' WHEN t.deleted THEN 'deleted' WHEN (.+) THEN 'renamed' ELSE '' END AS broken FROM item_links il LEFT JOIN LATERAL \\( SELECT (.+) WHERE ti.id = il.target_id AND ul.user_id = \\$1 LIMIT 1 \\) t on true WHERE il.source_id = \\$2 ORDER BY il.ref").
+		WithArgs(1, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"source_id", "ref", "target_id", "title", "broken"}).
+			AddRow(4, "#7", 7, "groceries", "").
+			AddRow(4, "Shopping", nil, nil, notes.LinkMissing))
+
+	got, err := r.GetLinks(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.ItemLink{
+		{SourceId: 4, Ref: "#7", TargetId: intPointer(7), Title: stringPointer("groceries")},
+		{SourceId: 4, Ref: "Shopping", Broken: notes.LinkMissing},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLinkPostgres_GetBacklinks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewLinkPostgres(sqlxDb)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "title", "description", "archived", "position", "due_at", "priority", "done", "done_at", "remind_at", "recurrence", "occurrence", "created_at", "updated_at", "version", "list_id"}
+
+	mock.ExpectQuery("SELECT (.+), li.list_id FROM notes_items ti (.+) WHERE ul.user_id = \\$1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL AND EXISTS \\(SELECT 1 FROM item_links il WHERE il.source_id = ti.id AND il.target_id = \\$2\\) ORDER BY ti.updated_at DESC, ti.id").
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "plan", "see [[groceries]]", false, "i", nil, 0, false, nil, nil, nil, 1, created, created, 2, 3))
+
+	got, err := r.GetBacklinks(1, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.ListedItem{{
+		NotesItem: notes.NotesItem{Id: 4, Title: "plan", Description: "see [[groceries]]", Position: "i", Occurrence: 1, CreatedAt: created, UpdatedAt: created, Version: 2},
+		ListId:    3,
+	}}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLinkPostgres_GetBroken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewLinkPostgres(sqlxDb)
+
+	mock.ExpectQuery("SELECT \\* FROM \\( SELECT il.source_id, (.+), s.title AS source_title, sl.list_id FROM item_links il (.+) WHERE su.user_id = \\$1 AND s.deleted_at IS NULL AND stl.deleted_at IS NULL \\) links WHERE broken <> '' ORDER BY list_id, source_id, ref").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"source_id", "ref", "target_id", "title", "broken", "source_title", "list_id"}).
+			AddRow(4, "#7", 7, "groceries", notes.LinkDeleted, "plan", 3))
+
+	got, err := r.GetBroken(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.BrokenLink{{
+		ItemLink:    notes.ItemLink{SourceId: 4, Ref: "#7", TargetId: intPointer(7), Title: stringPointer("groceries"), Broken: notes.LinkDeleted},
+		SourceTitle: "plan",
+		ListId:      3,
+	}}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
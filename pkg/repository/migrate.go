@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	schemaMigrationsTable = "schema_migrations"
+
+	// migrationLockId is the pg_advisory_lock key held while migrating, so
+	// replicas starting at the same time apply the schema one at a time.
+	migrationLockId = 7246580331
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Pending []Migration
+}
+
+// Migrator applies the numbered *.up.sql/*.down.sql files of a schema
+// directory. Applied versions are recorded in schema_migrations using the
+// same layout as the golang-migrate CLI, so existing databases carry over.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		parts := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || parts == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: parts[2]}
+			byVersion[uint(version)] = m
+		}
+
+		if parts[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up() error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+
+	return m.To(m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down rolls back the given number of applied migrations.
+func (m *Migrator) Down(steps int) error {
+	return m.withLock(func(conn *sqlx.Conn) error {
+		current, err := m.currentVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		if current == 0 {
+			return nil
+		}
+
+		idx := m.indexOf(current)
+		if idx < 0 {
+			return fmt.Errorf("unknown migration version %d", current)
+		}
+
+		target := uint(0)
+		if idx-steps >= 0 {
+			target = m.migrations[idx-steps].Version
+		}
+
+		return m.migrate(conn, current, target)
+	})
+}
+
+// To migrates up or down until the given version is the current one.
+// Version 0 rolls back every migration.
+func (m *Migrator) To(version uint) error {
+	if version != 0 && m.indexOf(version) < 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(func(conn *sqlx.Conn) error {
+		current, err := m.currentVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(conn, current, version)
+	})
+}
+
+// Force records the given version as applied and clears the dirty flag
+// without running any SQL. It is the way out of a failed migration left
+// behind by the external migrate tool.
+func (m *Migrator) Force(version uint) error {
+	return m.withLock(func(conn *sqlx.Conn) error {
+		tx, err := conn.BeginTxx(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+
+		if err := setVersion(tx, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// Status reports the current version and the pending migrations. It only
+// reads, so it doesn't wait for the migration lock and doesn't create the
+// version table; a database that was never migrated is at version 0.
+func (m *Migrator) Status() (MigrationStatus, error) {
+	var status MigrationStatus
+
+	row := m.db.QueryRowx(fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", schemaMigrationsTable))
+	if err := row.Scan(&status.Version, &status.Dirty); err != nil && !errors.Is(err, sql.ErrNoRows) && !isUndefinedTable(err) {
+		return status, err
+	}
+
+	for _, mg := range m.migrations {
+		if mg.Version > status.Version {
+			status.Pending = append(status.Pending, mg)
+		}
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) migrate(conn *sqlx.Conn, current, target uint) error {
+	if target > current {
+		for _, mg := range m.migrations {
+			if mg.Version <= current || mg.Version > target {
+				continue
+			}
+
+			logrus.Infof("applying migration %d_%s", mg.Version, mg.Name)
+			if err := m.apply(conn, mg.Up, mg.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mg.Version, mg.Name, err)
+			}
+		}
+
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if mg.Version > current || mg.Version <= target {
+			continue
+		}
+
+		if mg.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", mg.Version, mg.Name)
+		}
+
+		prev := uint(0)
+		if i > 0 {
+			prev = m.migrations[i-1].Version
+		}
+
+		logrus.Infof("reverting migration %d_%s", mg.Version, mg.Name)
+		if err := m.apply(conn, mg.Down, prev); err != nil {
+			return fmt.Errorf("migration %d_%s down: %w", mg.Version, mg.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// apply runs one migration script and records the resulting version in the
+// same transaction, so a failing script leaves the schema untouched.
+func (m *Migrator) apply(conn *sqlx.Conn, script string, version uint) error {
+	tx, err := conn.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := setVersion(tx, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) currentVersion(conn *sqlx.Conn) (uint, error) {
+	var (
+		version uint
+		dirty   bool
+	)
+
+	row := conn.QueryRowxContext(context.Background(), fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", schemaMigrationsTable))
+	if err := row.Scan(&version, &dirty); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if dirty {
+		return version, fmt.Errorf("database is dirty at version %d, fix it manually and force the version", version)
+	}
+
+	return version, nil
+}
+
+func (m *Migrator) indexOf(version uint) int {
+	for i, mg := range m.migrations {
+		if mg.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock. Advisory locks belong to the session, so lock, work and unlock must
+// all go through the same connection.
+func (m *Migrator) withLock(fn func(conn *sqlx.Conn) error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockId); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockId)
+
+	createTableQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)", schemaMigrationsTable)
+	if _, err := conn.ExecContext(ctx, createTableQuery); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func setVersion(tx *sqlx.Tx, version uint) error {
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", schemaMigrationsTable)); err != nil {
+		return err
+	}
+
+	if version == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, false)", schemaMigrationsTable), version)
+
+	return err
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app/schema"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = fstest.MapFS{
+	"000001_init.up.sql":     {Data: []byte("CREATE TABLE a (id int);")},
+	"000001_init.down.sql":   {Data: []byte("DROP TABLE a;")},
+	"000002_second.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+	"000002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+	"README.md":              {Data: []byte("ignored")},
+}
+
+func expectLocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(migrationLockId).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockId).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, script string, version int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(version).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestNewMigrator_Embedded(t *testing.T) {
+	m, err := NewMigrator(nil, schema.Migrations)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m.migrations)
+	assert.Equal(t, uint(1), m.migrations[0].Version)
+
+	for _, mg := range m.migrations {
+		assert.NotEmpty(t, mg.Up, "version %d", mg.Version)
+		assert.NotEmpty(t, mg.Down, "version %d", mg.Version)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	m, err := NewMigrator(sqlxDb, testMigrations)
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for migrations", err)
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		run     func() error
+		wantErr bool
+	}{
+		{
+			name: "Up_Fresh",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+				expectApplied(mock, "CREATE TABLE a", 1)
+				expectApplied(mock, "CREATE TABLE b", 2)
+				expectUnlocked(mock)
+			},
+			run: m.Up,
+		},
+		{
+			name: "Up_Partial",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, false))
+				expectApplied(mock, "CREATE TABLE b", 2)
+				expectUnlocked(mock)
+			},
+			run: m.Up,
+		},
+		{
+			name: "Down_OneStep",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(2, false))
+				expectApplied(mock, "DROP TABLE b", 1)
+				expectUnlocked(mock)
+			},
+			run: func() error { return m.Down(1) },
+		},
+		{
+			name: "To_Zero",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(2, false))
+				expectApplied(mock, "DROP TABLE b", 1)
+				expectApplied(mock, "DROP TABLE a", 0)
+				expectUnlocked(mock)
+			},
+			run: func() error { return m.To(0) },
+		},
+		{
+			name:    "To_Unknown",
+			mock:    func() {},
+			run:     func() error { return m.To(5) },
+			wantErr: true,
+		},
+		{
+			name: "Dirty",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, true))
+				expectUnlocked(mock)
+			},
+			run:     m.Up,
+			wantErr: true,
+		},
+		{
+			name: "Failing Script",
+			mock: func() {
+				expectLocked(mock)
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, false))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE b").WillReturnError(errors.New("syntax error"))
+				mock.ExpectRollback()
+				expectUnlocked(mock)
+			},
+			run:     m.Up,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := tt.run()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	m, err := NewMigrator(sqlxDb, testMigrations)
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for migrations", err)
+	}
+
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, false))
+
+	status, err := m.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), status.Version)
+	assert.False(t, status.Dirty)
+	if assert.Len(t, status.Pending, 1) {
+		assert.Equal(t, "second", status.Pending[0].Name)
+	}
+
+	// A database that was never migrated has no version table yet.
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnError(&pq.Error{Code: "42P01"})
+
+	status, err = m.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), status.Version)
+	assert.Len(t, status.Pending, 2)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
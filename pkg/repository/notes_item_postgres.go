@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Liopun/notes-app"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type NotesItemPostgres struct {
@@ -16,56 +19,203 @@ func NewNotesItemPostgres(db *sqlx.DB) *NotesItemPostgres {
 	return &NotesItemPostgres{db: db}
 }
 
-func (r *NotesItemPostgres) Create(userId int, item notes.NotesItem) (int, error) {
-	var itemId int
+// itemColumns are the columns of notes_items aliased as ti that make up a
+// notes.NotesItem.
+const itemColumns = "ti.id, ti.title, ti.description, ti.format, ti.archived, ti.position, ti.due_at, ti.priority, ti.done, ti.done_at, ti.remind_at, ti.recurrence, ti.occurrence, ti.created_at, ti.updated_at, ti.version"
+
+// createRevisionQuery records the current state of item $1 as a revision
+// authored by user $2.
+var createRevisionQuery = fmt.Sprintf(
+	"INSERT INTO %s (item_id, version, title, description, archived, author_id) SELECT id, version, title, description, archived, $2 FROM %s WHERE id = $1",
+	itemRevisionsTable,
+	notesItemsTable,
+)
+
+// lastItemPositionQuery returns the greatest position in list $1, trashed
+// items included so that they keep their place when restored.
+var lastItemPositionQuery = fmt.Sprintf(
+	"SELECT COALESCE(max(ti.position), '') FROM %s ti INNER JOIN %s li on li.item_id = ti.id WHERE li.list_id = $1",
+	notesItemsTable,
+	listsItemsTable,
+)
+
+// lastItemPosition returns the greatest position in list listId, inside tx.
+// The list is locked first, like Reorder does, so that concurrent appends
+// to it can't pick the same position.
+func lastItemPosition(tx *sql.Tx, listId int) (string, error) {
+	var last string
+
+	lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", notesListsTable)
+	if _, err := tx.Exec(lockQuery, listId); err != nil {
+		return "", err
+	}
 
+	err := tx.QueryRow(lastItemPositionQuery, listId).Scan(&last)
+
+	return last, err
+}
+
+// Create adds the item at the end of the list and records it as the first
+// revision, authored by userId.
+func (r *NotesItemPostgres) Create(userId, listId int, item notes.NotesItem) (int, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return -1, err
 	}
 
-	createItemQuery := fmt.Sprintf("INSERT INTO %s (title, description) values ($1, $2) RETURNING id", notesItemsTable)
-	row := tx.QueryRow(createItemQuery, item.Title, item.Description)
-	if err := row.Scan(&itemId); err != nil {
+	itemId, err := insertItem(tx, userId, listId, item)
+	if err != nil {
 		tx.Rollback()
 		return -1, err
 	}
 
-	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
-	_, err = tx.Exec(createListItemsQuery, userId, itemId)
+	return itemId, tx.Commit()
+}
+
+// insertItem is Create within tx.
+func insertItem(tx *sql.Tx, userId, listId int, item notes.NotesItem) (int, error) {
+	var itemId int
+
+	last, err := lastItemPosition(tx, listId)
 	if err != nil {
-		tx.Rollback()
 		return -1, err
 	}
 
-	return itemId, tx.Commit()
+	createItemQuery := fmt.Sprintf("INSERT INTO %s (title, description, format, position, due_at, priority, remind_at, recurrence, occurrence) values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id", notesItemsTable)
+	row := tx.QueryRow(createItemQuery, item.Title, item.Description, item.Format, rankAfter(last), item.DueAt, item.Priority, item.RemindAt, item.Recurrence, item.Occurrence)
+	if err := row.Scan(&itemId); err != nil {
+		return -1, err
+	}
+
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
+	if _, err := tx.Exec(createListItemsQuery, listId, itemId); err != nil {
+		return -1, err
+	}
+
+	if _, err := tx.Exec(createRevisionQuery, itemId, userId); err != nil {
+		return -1, err
+	}
+
+	if err := setLinks(tx, userId, itemId, item.Description); err != nil {
+		return -1, err
+	}
+
+	return itemId, nil
 }
 
-func (r *NotesItemPostgres) GetAll(userId, listId int) ([]notes.NotesItem, error) {
+// GetAll returns one page of the items of a list and the cursor of the
+// next page, which is empty on the last one.
+func (r *NotesItemPostgres) GetAll(userId, listId int, q notes.ItemsQuery) ([]notes.NotesItem, string, error) {
 	var items []notes.NotesItem
 
+	p, err := newPage(q.PageQuery)
+	if err != nil {
+		return nil, "", err
+	}
+
+	args := []interface{}{listId, userId}
+	filter := ""
+	if q.Archived != nil {
+		args = append(args, *q.Archived)
+		filter += fmt.Sprintf(" AND ti.archived = $%d", len(args))
+	}
+
+	if q.ModifiedSince != nil {
+		args = append(args, *q.ModifiedSince)
+		filter += fmt.Sprintf(" AND ti.updated_at > $%d", len(args))
+	}
+
+	where, tail, pageArgs, err := p.clause("ti", len(args)+1)
+	if err != nil {
+		return nil, "", err
+	}
+
 	query := fmt.Sprintf(
-		`SELECT ti.id, ti.title, ti.description, ti.archived FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id WHERE li.list_id = $1 AND ul.user_id = $2`,
+		`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE li.list_id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL%s%s%s`,
+		itemColumns,
 		notesItemsTable,
 		listsItemsTable,
 		usersListsTable,
+		notesListsTable,
+		filter,
+		where,
+		tail,
 	)
+	args = append(args, pageArgs...)
 
-	if err := r.db.Select(&items, query, listId, userId); err != nil {
-		return nil, err
+	if err := r.db.Select(&items, query, args...); err != nil {
+		return nil, "", err
+	}
+
+	fetched := len(items)
+	if fetched > p.Limit {
+		items = items[:p.Limit]
+	}
+
+	if err := r.addChecklistProgress(items); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		next = p.next(fetched, last.Id, last.Title, last.Position, last.CreatedAt, last.UpdatedAt)
+	}
+
+	return items, next, nil
+}
+
+// addChecklistProgress fills in the checklist progress of the items that
+// have a checklist.
+func (r *NotesItemPostgres) addChecklistProgress(items []notes.NotesItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+
+	var counts []struct {
+		ItemId int `db:"item_id"`
+		notes.ChecklistProgress
+	}
+
+	query := fmt.Sprintf(
+		"SELECT item_id, count(*) FILTER (WHERE checked) AS checklist_done, count(*) AS checklist_total FROM %s WHERE item_id = ANY($1) GROUP BY item_id",
+		checklistEntriesTable,
+	)
+	if err := r.db.Select(&counts, query, pq.Array(ids)); err != nil {
+		return err
 	}
 
-	return items, nil
+	progress := make(map[int]notes.ChecklistProgress, len(counts))
+	for _, c := range counts {
+		progress[c.ItemId] = c.ChecklistProgress
+	}
+
+	for i := range items {
+		if p, ok := progress[items[i].Id]; ok {
+			items[i].Checklist = &p
+		}
+	}
+
+	return nil
 }
 
 func (r *NotesItemPostgres) GetById(userId, itemId int) (notes.NotesItem, error) {
 	var item notes.NotesItem
 
 	query := fmt.Sprintf(
-		`SELECT ti.id, ti.title, ti.description, ti.archived FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2`,
+		`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE ti.id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL`,
+		itemColumns,
 		notesItemsTable,
 		listsItemsTable,
 		usersListsTable,
+		notesListsTable,
 	)
 
 	if err := r.db.Get(&item, query, itemId, userId); err != nil {
@@ -75,20 +225,67 @@ func (r *NotesItemPostgres) GetById(userId, itemId int) (notes.NotesItem, error)
 	return item, nil
 }
 
-func (r *NotesItemPostgres) Delete(userId, itemId int) error {
+// GetDue returns the open items of all of the user's lists that are due
+// within [from, to), soonest and most urgent first. A nil bound is open.
+// Items that are done or archived are left out.
+func (r *NotesItemPostgres) GetDue(userId int, from, to *time.Time) ([]notes.ListedItem, error) {
+	var items []notes.ListedItem
+
+	args := []interface{}{userId}
+	filter := ""
+	if from != nil {
+		args = append(args, *from)
+		filter += fmt.Sprintf(" AND ti.due_at >= $%d", len(args))
+	}
+
+	if to != nil {
+		args = append(args, *to)
+		filter += fmt.Sprintf(" AND ti.due_at < $%d", len(args))
+	}
+
 	query := fmt.Sprintf(
-		`DELETE FROM %s ti USING %s li, %s ul WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2`,
+		`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE ul.user_id = $1 AND ti.due_at IS NOT NULL AND NOT ti.done AND NOT ti.archived AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL%s
+		ORDER BY ti.due_at ASC, ti.priority DESC, ti.id ASC`,
+		itemColumns,
 		notesItemsTable,
 		listsItemsTable,
 		usersListsTable,
+		notesListsTable,
+		filter,
 	)
+	err := r.db.Select(&items, query, args...)
 
-	_, err := r.db.Exec(query, userId, itemId)
+	return items, err
+}
 
-	return err
+// Delete moves the item to the trash. A non-zero version must match the
+// current one, otherwise notes.ErrVersionConflict is returned.
+func (r *NotesItemPostgres) Delete(userId, itemId, version int) error {
+	query := fmt.Sprintf(
+		`UPDATE %s ti SET deleted_at=now() FROM %s li, %s ul WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2 AND ul.role IN (%s) AND ti.deleted_at IS NULL AND ($3 = 0 OR ti.version = $3)`,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		writeRoles,
+	)
+
+	res, err := r.db.Exec(query, userId, itemId, version)
+	if err != nil {
+		return err
+	}
+
+	if version != 0 {
+		return versionError(expectAffected(res), version)
+	}
+
+	return nil
 }
 
-func (r *NotesItemPostgres) Update(userId, itemId int, inp notes.UpdateItemInput) error {
+// Update applies inp and returns the new version of the item. A non-zero
+// version must match the current one, otherwise notes.ErrVersionConflict is
+// returned and nothing is changed.
+func (r *NotesItemPostgres) Update(userId, itemId int, inp notes.UpdateItemInput, version int, next NextOccurrence) (int, error) {
 	qValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -105,27 +302,428 @@ func (r *NotesItemPostgres) Update(userId, itemId int, inp notes.UpdateItemInput
 		argId++
 	}
 
+	if inp.Format != nil {
+		qValues = append(qValues, fmt.Sprintf("format=$%d", argId))
+		args = append(args, *inp.Format)
+		argId++
+	}
+
 	if inp.Archived != nil {
 		qValues = append(qValues, fmt.Sprintf("archived=$%d", argId))
 		args = append(args, *inp.Archived)
 		argId++
 	}
 
+	if inp.DueAt.Set {
+		qValues = append(qValues, fmt.Sprintf("due_at=$%d", argId))
+		args = append(args, inp.DueAt.Time)
+		argId++
+	}
+
+	if inp.Priority != nil {
+		qValues = append(qValues, fmt.Sprintf("priority=$%d", argId))
+		args = append(args, *inp.Priority)
+		argId++
+	}
+
+	// Marking a done item done again keeps the time it was first done.
+	if inp.Done != nil {
+		qValues = append(qValues, fmt.Sprintf("done=$%d", argId), fmt.Sprintf("done_at=CASE WHEN $%d THEN COALESCE(ti.done_at, now()) END", argId))
+		args = append(args, *inp.Done)
+		argId++
+	}
+
+	if inp.RemindAt.Set {
+		qValues = append(qValues, fmt.Sprintf("remind_at=$%d", argId), "reminded_at=NULL")
+		args = append(args, inp.RemindAt.Time)
+		argId++
+	}
+
+	if inp.Recurrence.Set {
+		qValues = append(qValues, fmt.Sprintf("recurrence=$%d", argId), "occurrence=1")
+		args = append(args, inp.Recurrence.String)
+		argId++
+	}
+
+	qValues = append(qValues, "updated_at=now()", "version=ti.version+1")
 	qString := strings.Join(qValues, ", ")
 
+	// The new state is recorded as a revision by the same statement, so
+	// history can't miss a write.
 	query := fmt.Sprintf(
-		`UPDATE %s ti SET %s FROM %s li, %s ul WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $%d AND ti.id = $%d`,
+		`WITH updated AS (
+			UPDATE %[1]s ti SET %[2]s FROM %[3]s li, %[4]s ul WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $%[5]d AND ti.id = $%[6]d AND ul.role IN (%[7]s) AND ti.deleted_at IS NULL AND ($%[8]d = 0 OR ti.version = $%[8]d)
+			RETURNING ti.id, ti.version, ti.title, ti.description, ti.archived
+		)
+		INSERT INTO %[9]s (item_id, version, title, description, archived, author_id)
+		SELECT id, version, title, description, archived, $%[5]d FROM updated RETURNING version`,
 		notesItemsTable,
 		qString,
 		listsItemsTable,
 		usersListsTable,
 		argId,
 		argId+1,
+		writeRoles,
+		argId+2,
+		itemRevisionsTable,
+	)
+
+	args = append(args, userId, itemId, version)
+
+	archiving := next != nil && inp.Archived != nil && *inp.Archived
+	renaming := inp.RewriteLinks && inp.Title != nil
+	if !archiving && !renaming && inp.Description == nil {
+		var newVersion int
+		if err := r.db.QueryRow(query, args...).Scan(&newVersion); err != nil {
+			return -1, versionError(err, version)
+		}
+
+		return newVersion, nil
+	}
+
+	return r.update(userId, itemId, inp, query, args, version, next)
+}
+
+// update runs the update query in a transaction together with what the
+// update entails: a new description replaces the item's links, a new title
+// with RewriteLinks set rewrites the links to the item by its old title,
+// and archiving an open occurrence of a recurring series adds the next
+// occurrence to the end of its list. The rule moves on to the new
+// occurrence, so archiving the old one again doesn't repeat it.
+func (r *NotesItemPostgres) update(userId, itemId int, inp notes.UpdateItemInput, query string, args []interface{}, version int, next NextOccurrence) (int, error) {
+	var (
+		item       notes.ListedItem
+		newVersion int
 	)
 
-	args = append(args, userId, itemId)
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return -1, err
+	}
+
+	currentQuery := fmt.Sprintf(
+		`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id WHERE ti.id = $1 AND ti.deleted_at IS NULL FOR UPDATE OF ti`,
+		itemColumns,
+		notesItemsTable,
+		listsItemsTable,
+	)
+	if err := tx.Get(&item, currentQuery, itemId); err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return -1, err
+	}
+
+	if err := tx.QueryRow(query, args...).Scan(&newVersion); err != nil {
+		tx.Rollback()
+		return -1, versionError(err, version)
+	}
 
-	_, err := r.db.Exec(query, args...)
+	if inp.Description != nil {
+		if err := setLinks(tx.Tx, userId, itemId, *inp.Description); err != nil {
+			tx.Rollback()
+			return -1, err
+		}
+	}
+
+	if inp.RewriteLinks && inp.Title != nil && *inp.Title != item.Title {
+		if err := rewriteLinks(tx, userId, itemId, *inp.Title); err != nil {
+			tx.Rollback()
+			return -1, err
+		}
+	}
+
+	if next != nil && inp.Archived != nil && *inp.Archived && !item.Archived && item.Recurrence != nil {
+		if err := r.spawnNext(tx, userId, item, next); err != nil {
+			tx.Rollback()
+			return -1, err
+		}
+	}
+
+	return newVersion, tx.Commit()
+}
+
+// spawnNext adds the occurrence following item, if the series goes on,
+// and ends the series on item.
+func (r *NotesItemPostgres) spawnNext(tx *sqlx.Tx, userId int, item notes.ListedItem, next NextOccurrence) error {
+	following, ok := next(item.NotesItem)
+	if !ok {
+		return nil
+	}
+
+	followingId, err := insertItem(tx.Tx, userId, item.ListId, following)
+	if err != nil {
+		return err
+	}
+
+	copyTagsQuery := fmt.Sprintf("INSERT INTO %s (item_id, tag_id) SELECT $2, tag_id FROM %s WHERE item_id = $1", itemTagsTable, itemTagsTable)
+	if _, err := tx.Exec(copyTagsQuery, item.Id, followingId); err != nil {
+		return err
+	}
+
+	if err := copyChecklist(tx.Tx, item.Id, followingId, false); err != nil {
+		return err
+	}
+
+	endQuery := fmt.Sprintf("UPDATE %s SET recurrence=NULL WHERE id = $1", notesItemsTable)
+	_, err = tx.Exec(endQuery, item.Id)
 
 	return err
 }
+
+// Move relinks the items to the end of the target list in one transaction.
+// The user must be able to edit both the lists the items are in and the
+// target list, and neither may be in the trash; unless every item qualifies
+// nothing is moved and sql.ErrNoRows is returned.
+func (r *NotesItemPostgres) Move(userId, targetListId int, itemIds []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	last, err := lastItemPosition(tx, targetListId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	moveQuery := fmt.Sprintf(
+		`UPDATE %[1]s li SET list_id = $3 FROM %[2]s ul, %[3]s ti, %[4]s sl
+		WHERE ul.list_id = li.list_id AND ti.id = li.item_id AND sl.id = li.list_id AND li.item_id = ANY($1) AND ul.user_id = $2 AND ul.role IN (%[5]s)
+		AND ti.deleted_at IS NULL AND sl.deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM %[2]s tu INNER JOIN %[4]s tl on tl.id = tu.list_id WHERE tu.list_id = $3 AND tu.user_id = $2 AND tu.role IN (%[5]s) AND tl.deleted_at IS NULL)`,
+		listsItemsTable,
+		usersListsTable,
+		notesItemsTable,
+		notesListsTable,
+		writeRoles,
+	)
+	res, err := tx.Exec(moveQuery, pq.Array(itemIds), userId, targetListId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	moved, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if moved != int64(len(itemIds)) {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	// Moving changes which list an item shows up in, so incremental syncs
+	// of either list must see it as modified.
+	placeQuery := fmt.Sprintf("UPDATE %s SET position=$1, updated_at=now() WHERE id = $2", notesItemsTable)
+	for _, itemId := range itemIds {
+		last = rankAfter(last)
+		if _, err := tx.Exec(placeQuery, last, itemId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Copy duplicates the items to the end of the target list in one
+// transaction and returns the ids of the copies, in the order of itemIds.
+// Each copy starts its own revision history and keeps the reminder and
+// recurrence of its original; a reminder that was already sent isn't sent
+// again for the copy. Items the user can't read, or whose list is in the
+// trash, are reported as sql.ErrNoRows and nothing is copied.
+func (r *NotesItemPostgres) Copy(userId, targetListId int, itemIds []int) ([]int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := lastItemPosition(tx, targetListId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	copyItemQuery := fmt.Sprintf(
+		`INSERT INTO %[1]s (title, description, format, archived, position, due_at, priority, done, done_at, remind_at, reminded_at, recurrence, occurrence)
+		SELECT ti.title, ti.description, ti.format, ti.archived, $3, ti.due_at, ti.priority, ti.done, ti.done_at, ti.remind_at, ti.reminded_at, ti.recurrence, ti.occurrence
+		FROM %[1]s ti INNER JOIN %[2]s li on li.item_id = ti.id INNER JOIN %[3]s ul on ul.list_id = li.list_id INNER JOIN %[4]s sl on sl.id = li.list_id
+		WHERE ti.id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL AND sl.deleted_at IS NULL LIMIT 1 RETURNING id`,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+	)
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
+
+	ids := make([]int, 0, len(itemIds))
+	for _, itemId := range itemIds {
+		var id int
+
+		last = rankAfter(last)
+		row := tx.QueryRow(copyItemQuery, itemId, userId, last)
+		if err := row.Scan(&id); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if _, err := tx.Exec(createListItemsQuery, targetListId, id); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if _, err := tx.Exec(createRevisionQuery, id, userId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := copyChecklist(tx, itemId, id, true); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := copyLinks(tx, itemId, id); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, tx.Commit()
+}
+
+// Reorder places the item directly after or before another item of its
+// list, changing the position of the moved item only. The other item must
+// be in the same list, otherwise sql.ErrNoRows is returned.
+func (r *NotesItemPostgres) Reorder(userId, itemId int, inp notes.ReorderInput) error {
+	var (
+		listId   int
+		anchor   string
+		neighbor string
+	)
+
+	anchorId, agg, cmp := inp.BeforeId, "max", "<"
+	if inp.AfterId != nil {
+		anchorId, agg, cmp = inp.AfterId, "min", ">"
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Locking the list serializes reorders within it, so that two of them
+	// can't pick the same free position.
+	anchorQuery := fmt.Sprintf(
+		`SELECT a.position, la.list_id FROM %[1]s a INNER JOIN %[2]s la on la.item_id = a.id INNER JOIN %[2]s lm on lm.list_id = la.list_id INNER JOIN %[3]s tl on tl.id = la.list_id
+		WHERE a.id = $1 AND lm.item_id = $2 AND a.id <> $2 AND a.deleted_at IS NULL FOR UPDATE OF tl`,
+		notesItemsTable,
+		listsItemsTable,
+		notesListsTable,
+	)
+	if err := tx.QueryRow(anchorQuery, *anchorId, itemId).Scan(&anchor, &listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	neighborQuery := fmt.Sprintf(
+		`SELECT COALESCE(%s(ti.position), '') FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+		WHERE li.list_id = $1 AND ti.id <> $2 AND ti.deleted_at IS NULL AND ti.position %s $3`,
+		agg,
+		notesItemsTable,
+		listsItemsTable,
+		cmp,
+	)
+	if err := tx.QueryRow(neighborQuery, listId, itemId, anchor).Scan(&neighbor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position := rankBetween(neighbor, anchor)
+	if inp.AfterId != nil {
+		position = rankBetween(anchor, neighbor)
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET position=$1, updated_at=now() WHERE id = $2", notesItemsTable)
+	if _, err := tx.Exec(updateQuery, position, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restore takes the item out of the trash, back into its list. The list
+// itself must not be in the trash; sql.ErrNoRows is returned when it is,
+// when the user can't edit it or when the item is not in the trash.
+func (r *NotesItemPostgres) Restore(userId, itemId int) error {
+	query := fmt.Sprintf(
+		`UPDATE %s ti SET deleted_at=NULL FROM %s li, %s ul, %s tl WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND tl.id = li.list_id
+		AND ul.user_id = $1 AND ti.id = $2 AND ul.role IN (%s) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL`,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+		writeRoles,
+	)
+
+	res, err := r.db.Exec(query, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// GetRole returns the strongest role the user holds on any list the item
+// belongs to. Trashed items, and items of trashed lists, report
+// sql.ErrNoRows.
+func (r *NotesItemPostgres) GetRole(userId, itemId int) (string, error) {
+	var role string
+
+	query := fmt.Sprintf(
+		`SELECT ul.role FROM %s li INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s ti on ti.id = li.item_id INNER JOIN %s tl on tl.id = li.list_id
+		WHERE li.item_id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+		ORDER BY array_position(%s, ul.role) LIMIT 1`,
+		listsItemsTable,
+		usersListsTable,
+		notesItemsTable,
+		notesListsTable,
+		roleOrder,
+	)
+	err := r.db.Get(&role, query, itemId, userId)
+
+	return role, err
+}
+
+// GetRevisions returns the history of an item, newest first, without the
+// descriptions.
+func (r *NotesItemPostgres) GetRevisions(itemId int) ([]notes.ItemRevision, error) {
+	var revisions []notes.ItemRevision
+
+	query := fmt.Sprintf(
+		`SELECT ir.item_id, ir.version, ir.title, ir.archived, u.username AS author, ir.created_at
+		FROM %s ir LEFT JOIN %s u on u.id = ir.author_id WHERE ir.item_id = $1 ORDER BY ir.version DESC`,
+		itemRevisionsTable,
+		usersTable,
+	)
+	err := r.db.Select(&revisions, query, itemId)
+
+	return revisions, err
+}
+
+func (r *NotesItemPostgres) GetRevision(itemId, version int) (notes.ItemRevision, error) {
+	var revision notes.ItemRevision
+
+	query := fmt.Sprintf(
+		`SELECT ir.item_id, ir.version, ir.title, ir.description, ir.archived, u.username AS author, ir.created_at
+		FROM %s ir LEFT JOIN %s u on u.id = ir.author_id WHERE ir.item_id = $1 AND ir.version = $2`,
+		itemRevisionsTable,
+		usersTable,
+	)
+	err := r.db.Get(&revision, query, itemId, version)
+
+	return revision, err
+}
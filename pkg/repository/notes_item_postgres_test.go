@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Liopun/notes-app"
@@ -24,6 +25,7 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 	r := NewNotesItemPostgres(sqlxDb)
 
 	type args struct {
+		userId int
 		listId int
 		item   notes.NotesItem
 	}
@@ -40,6 +42,7 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 		{
 			name: "OK",
 			input: args{
+				userId: 1,
 				listId: 1,
 				item: notes.NotesItem{
 					Title:       "test title",
@@ -51,8 +54,44 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 
-				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(args.listId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items ti (.+) WHERE li.list_id = \\$1").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description, "", "i0001", nil, 0, nil, nil, 0).WillReturnRows(rows)
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO item_revisions (.+) SELECT (.+) FROM notes_items WHERE id = (.+)").WithArgs(id, args.userId).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM item_links WHERE source_id = \\$1").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "OK_Links",
+			input: args{
+				userId: 1,
+				listId: 1,
+				item: notes.NotesItem{
+					Title:       "test title",
+					Description: "see [[Groceries]], [[ #012 ]] and [[groceries]]",
+				},
+			},
+			mock: func(args args, id int) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(args.listId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\)").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description, "", "i0001", nil, 0, nil, nil, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
 				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO item_revisions").WithArgs(id, args.userId).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM item_links WHERE source_id = \\$1").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO item_links \\(source_id, ref, target_id\\) SELECT \\$1, r.ref, \\( SELECT ti.id FROM notes_items ti (.+) ORDER BY (.+) LIMIT 1 \\) FROM unnest\\(\\$3::varchar\\[\\]\\) AS r\\(ref\\)").
+					WithArgs(id, args.userId, `{"Groceries","#12"}`).
+					WillReturnResult(sqlmock.NewResult(0, 2))
 
 				mock.ExpectCommit()
 			},
@@ -61,6 +100,7 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 		{
 			name: "Empty Fields",
 			input: args{
+				userId: 1,
 				listId: 1,
 				item: notes.NotesItem{
 					Title:       "",
@@ -71,7 +111,11 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 				mock.ExpectBegin()
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(0, errors.New("insert error"))
-				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(args.listId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items ti (.+) WHERE li.list_id = \\$1").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description, "", "i0001", nil, 0, nil, nil, 0).WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -80,6 +124,7 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 		{
 			name: "2nd Insert Failure",
 			input: args{
+				userId: 1,
 				listId: 1,
 				item: notes.NotesItem{
 					Title:       "title",
@@ -90,7 +135,11 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 				mock.ExpectBegin()
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
-				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(args.listId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items ti (.+) WHERE li.list_id = \\$1").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description, "", "i0001", nil, 0, nil, nil, 0).WillReturnRows(rows)
 
 				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).WillReturnError(errors.New("insert error"))
 
@@ -98,13 +147,40 @@ func TestNotesItemPostgres_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Revision Insert Failure",
+			input: args{
+				userId: 1,
+				listId: 1,
+				item: notes.NotesItem{
+					Title:       "title",
+					Description: "description",
+				},
+			},
+			mock: func(args args, id int) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(args.listId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items ti (.+) WHERE li.list_id = \\$1").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO notes_items").WithArgs(args.item.Title, args.item.Description, "", "i0001", nil, 0, nil, nil, 0).WillReturnRows(rows)
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO item_revisions").WithArgs(id, args.userId).WillReturnError(errors.New("insert error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock(tt.input, tt.want)
 
-			got, err := r.Create(tt.input.listId, tt.input.item)
+			got, err := r.Create(tt.input.userId, tt.input.listId, tt.input.item)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -129,9 +205,13 @@ func TestNotesItemPostgres_GetAll(t *testing.T) {
 
 	r := NewNotesItemPostgres(sqlxDb)
 
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "title", "description", "archived", "created_at", "updated_at"}
+
 	type args struct {
 		userId int
 		listId int
+		query  notes.ItemsQuery
 	}
 
 	tests := []struct {
@@ -145,25 +225,48 @@ func TestNotesItemPostgres_GetAll(t *testing.T) {
 			name:  "OK",
 			input: args{userId: 1, listId: 1},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "archived"}).
-					AddRow(1, "title1", "description1", false).
-					AddRow(2, "title2", "description2", true).
-					AddRow(3, "title3", "description3", true)
-
-				mock.ExpectQuery("SELECT (.+) FROM notes_items ti INNER JOIN lists_items li on (.+) INNER JOIN users_lists ul on (.+) WHERE (.+)").WithArgs(1, 1).WillReturnRows(rows)
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "title1", "description1", false, created, created).
+					AddRow(2, "title2", "description2", true, created, created).
+					AddRow(3, "title3", "description3", true, created, created)
+
+				mock.ExpectQuery("SELECT (.+) FROM notes_items ti INNER JOIN lists_items li on (.+) INNER JOIN users_lists ul on (.+) INNER JOIN notes_lists tl on (.+) WHERE (.+) AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL ORDER BY ti.position ASC, ti.id ASC LIMIT \\$3").
+					WithArgs(1, 1, notes.DefaultPageLimit+1).
+					WillReturnRows(rows)
+				mock.ExpectQuery("SELECT item_id, count\\(\\*\\) FILTER \\(WHERE checked\\) AS checklist_done, count\\(\\*\\) AS checklist_total FROM checklist_entries WHERE item_id = ANY\\(\\$1\\) GROUP BY item_id").
+					WithArgs("{1,2,3}").
+					WillReturnRows(sqlmock.NewRows([]string{"item_id", "checklist_done", "checklist_total"}).AddRow(2, 3, 7))
 			},
 			want: []notes.NotesItem{
-				{Id: 1, Title: "title1", Description: "description1", Archived: false},
-				{Id: 2, Title: "title2", Description: "description2", Archived: true},
-				{Id: 3, Title: "title3", Description: "description3", Archived: true},
+				{Id: 1, Title: "title1", Description: "description1", Archived: false, CreatedAt: created, UpdatedAt: created},
+				{Id: 2, Title: "title2", Description: "description2", Archived: true, CreatedAt: created, UpdatedAt: created, Checklist: &notes.ChecklistProgress{Done: 3, Total: 7}},
+				{Id: 3, Title: "title3", Description: "description3", Archived: true, CreatedAt: created, UpdatedAt: created},
+			},
+		},
+		{
+			name:  "Archived Filter",
+			input: args{userId: 1, listId: 1, query: notes.ItemsQuery{Archived: boolPointer(false), PageQuery: notes.PageQuery{Sort: notes.SortUpdated, Limit: 10}}},
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "title1", "description1", false, created, created)
+
+				mock.ExpectQuery("WHERE (.+) AND ti.archived = \\$3 ORDER BY ti.updated_at ASC, ti.id ASC LIMIT \\$4").
+					WithArgs(1, 1, false, 11).
+					WillReturnRows(rows)
+				mock.ExpectQuery("FROM checklist_entries").
+					WithArgs("{1}").
+					WillReturnRows(sqlmock.NewRows([]string{"item_id", "checklist_done", "checklist_total"}))
+			},
+			want: []notes.NotesItem{
+				{Id: 1, Title: "title1", Description: "description1", Archived: false, CreatedAt: created, UpdatedAt: created},
 			},
 		},
 		{
 			name:  "No Items",
 			input: args{userId: 1, listId: 1},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "archived"})
-				mock.ExpectQuery("SELECT (.+) FROM notes_items ti INNER JOIN lists_items li on (.+) INNER JOIN users_lists ul on (.+) WHERE (.+)").WithArgs(1, 1).WillReturnRows(rows)
+				rows := sqlmock.NewRows(columns)
+				mock.ExpectQuery("SELECT (.+) FROM notes_items ti INNER JOIN lists_items li on (.+) INNER JOIN users_lists ul on (.+) WHERE (.+)").WithArgs(1, 1, notes.DefaultPageLimit+1).WillReturnRows(rows)
 			},
 		},
 	}
@@ -172,12 +275,13 @@ func TestNotesItemPostgres_GetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetAll(tt.input.userId, tt.input.listId)
+			got, next, err := r.GetAll(tt.input.userId, tt.input.listId, tt.input.query)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.want, got)
+				assert.Empty(t, next)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -260,8 +364,9 @@ func TestNotesItemPostgres_Delete(t *testing.T) {
 	r := NewNotesItemPostgres(sqlxDb)
 
 	type args struct {
-		userId int
-		itemId int
+		userId  int
+		itemId  int
+		version int
 	}
 
 	tests := []struct {
@@ -275,8 +380,8 @@ func TestNotesItemPostgres_Delete(t *testing.T) {
 			name:  "OK",
 			input: args{userId: 1, itemId: 1},
 			mock: func() {
-				mock.ExpectExec("DELETE FROM notes_items ti USING lists_items li, users_lists ul WHERE (.+)").
-					WithArgs(1, 1).
+				mock.ExpectExec("UPDATE notes_items ti SET deleted_at=now\\(\\) FROM lists_items li, users_lists ul WHERE (.+) AND ti.deleted_at IS NULL").
+					WithArgs(1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
 			},
@@ -285,19 +390,29 @@ func TestNotesItemPostgres_Delete(t *testing.T) {
 			name:  "No Item",
 			input: args{userId: -1, itemId: -1},
 			mock: func() {
-				mock.ExpectExec("DELETE FROM notes_items ti USING lists_items li, users_lists ul WHERE (.+)").
-					WithArgs(-1, -1).
+				mock.ExpectExec("UPDATE notes_items ti SET deleted_at=now\\(\\) FROM lists_items li, users_lists ul WHERE (.+) AND ti.deleted_at IS NULL").
+					WithArgs(-1, -1, 0).
 					WillReturnError(sql.ErrNoRows)
 			},
 			wantErr: true,
 		},
+		{
+			name:  "Version Conflict",
+			input: args{userId: 1, itemId: 1, version: 3},
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_items ti SET deleted_at(.+) AND \\(\\$3 = 0 OR ti.version = \\$3\\)").
+					WithArgs(1, 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.Delete(tt.input.userId, tt.input.itemId)
+			err := r.Delete(tt.input.userId, tt.input.itemId, tt.input.version)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -322,16 +437,32 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 	r := NewNotesItemPostgres(sqlxDb)
 
 	type args struct {
-		userId int
-		itemId int
-		input  notes.UpdateItemInput
+		userId  int
+		itemId  int
+		input   notes.UpdateItemInput
+		version int
+		next    NextOccurrence
 	}
 
+	highPriority := notes.PriorityHigh
+	weekly := "FREQ=WEEKLY"
+	dueAt := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	nextDueAt := dueAt.AddDate(0, 0, 7)
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	following := func(item notes.NotesItem) (notes.NotesItem, bool) {
+		return notes.NotesItem{Title: item.Title, DueAt: &nextDueAt, Recurrence: item.Recurrence, Occurrence: item.Occurrence + 1}, true
+	}
+	ended := func(item notes.NotesItem) (notes.NotesItem, bool) {
+		return notes.NotesItem{}, false
+	}
+	currentColumns := []string{"id", "title", "description", "archived", "position", "due_at", "recurrence", "occurrence", "created_at", "updated_at", "version", "list_id"}
+
 	tests := []struct {
 		name    string
 		input   args
 		mock    func()
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "OK_Archived",
@@ -345,10 +476,17 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs("updated title", "updated desc", true, 1, 1).
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "title", "desc", false, "i", nil, nil, 1, created, created, 1, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+) AND ul.role IN (.+'owner', 'editor'.+) INSERT INTO item_revisions (.+) SELECT (.+) FROM updated RETURNING version").
+					WithArgs("updated title", "updated desc", true, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				mock.ExpectExec("DELETE FROM item_links WHERE source_id = \\$1").
+					WithArgs(1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
-
+				mock.ExpectCommit()
 			},
 		},
 		{
@@ -358,14 +496,24 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 				itemId: 1,
 				input: notes.UpdateItemInput{
 					Title:       stringPointer("updated title"),
-					Description: stringPointer("updated desc"),
+					Description: stringPointer("updated desc, see [[#4]]"),
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs("updated title", "updated desc", 1, 1).
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "title", "desc", false, "i", nil, nil, 1, created, created, 1, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
+					WithArgs("updated title", "updated desc, see [[#4]]", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				mock.ExpectExec("DELETE FROM item_links WHERE source_id = \\$1").
+					WithArgs(1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
-
+				mock.ExpectExec("INSERT INTO item_links").
+					WithArgs(1, 1, `{"#4"}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 		},
 		{
@@ -378,20 +526,209 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs("updated title", 1, 1).
+				mock.ExpectQuery("UPDATE notes_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
+					WithArgs("updated title", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "OK_DueAndDone",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input: notes.UpdateItemInput{
+					DueAt:    notes.NullableTime{Set: true},
+					Priority: &highPriority,
+					Done:     boolPointer(true),
+				},
+			},
+			mock: func() {
+				mock.ExpectQuery("UPDATE notes_items ti SET due_at=\\$1, priority=\\$2, done=\\$3, done_at=CASE WHEN \\$3 THEN COALESCE\\(ti.done_at, now\\(\\)\\) END, updated_at=now\\(\\)").
+					WithArgs(nil, notes.PriorityHigh, true, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "OK_Recurrence",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input:  notes.UpdateItemInput{Recurrence: notes.NullableString{Set: true, String: &weekly}},
+			},
+			mock: func() {
+				mock.ExpectQuery("UPDATE notes_items ti SET recurrence=\\$1, occurrence=1, updated_at=now\\(\\)").
+					WithArgs(weekly, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "OK_Format",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input:  notes.UpdateItemInput{Format: stringPointer(notes.FormatMarkdown)},
+			},
+			mock: func() {
+				mock.ExpectQuery("UPDATE notes_items ti SET format=\\$1, updated_at=now\\(\\)").
+					WithArgs(notes.FormatMarkdown, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "OK_RewriteLinks",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input:  notes.UpdateItemInput{Title: stringPointer("Shopping"), RewriteLinks: true},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "Groceries", "", false, "i", nil, nil, 1, created, created, 1, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET title=\\$1").
+					WithArgs("Shopping", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				mock.ExpectQuery("SELECT ti.id, ti.description, il.ref FROM item_links il (.+) WHERE il.target_id = \\$1 AND il.ref NOT LIKE '#%' AND ti.id <> \\$1 AND ul.user_id = \\$2 AND ul.role IN (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "description", "ref"}).
+						AddRow(5, "buy [[groceries]]", "groceries").
+						AddRow(6, "[[Groceries]] moved to [[Shopping]]", "Groceries"))
+				mock.ExpectExec("UPDATE notes_items SET description=\\$1, updated_at=now\\(\\), version=version\\+1 WHERE id = \\$2").
+					WithArgs("buy [[Shopping]]", 5).
 					WillReturnResult(sqlmock.NewResult(0, 1))
-
+				mock.ExpectExec("INSERT INTO item_revisions").
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM item_links WHERE source_id = \\$1 AND lower\\(ref\\) = lower\\(\\$3\\) AND ref <> \\$2").
+					WithArgs(5, "groceries", "Shopping").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE item_links SET ref=\\$3 WHERE source_id = \\$1 AND ref = \\$2").
+					WithArgs(5, "groceries", "Shopping").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE notes_items SET description").
+					WithArgs("[[Shopping]] moved to [[Shopping]]", 6).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO item_revisions").
+					WithArgs(6, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM item_links").
+					WithArgs(6, "Groceries", "Shopping").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE item_links SET ref").
+					WithArgs(6, "Groceries", "Shopping").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK_RecurringArchived",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input:  notes.UpdateItemInput{Archived: boolPointer(true)},
+				next:   following,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+), li.list_id FROM notes_items ti INNER JOIN lists_items li on li.item_id = ti.id WHERE ti.id = \\$1 AND ti.deleted_at IS NULL FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "standup", "", false, "i", dueAt, weekly, 2, created, created, 1, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET archived=\\$1").
+					WithArgs(true, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\)").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("i"))
+				mock.ExpectQuery("INSERT INTO notes_items").
+					WithArgs("standup", "", "", "j", &nextDueAt, 0, nil, weekly, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+				mock.ExpectExec("INSERT INTO lists_items").
+					WithArgs(7, 9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO item_revisions").
+					WithArgs(9, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM item_links").
+					WithArgs(9).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO item_tags \\(item_id, tag_id\\) SELECT \\$2, tag_id FROM item_tags WHERE item_id = \\$1").
+					WithArgs(1, 9).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO checklist_entries \\(item_id, text, checked, position\\) SELECT \\$2, text, checked AND \\$3, position FROM checklist_entries WHERE item_id = \\$1").
+					WithArgs(1, 9, false).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec("UPDATE notes_items SET recurrence=NULL WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK_SeriesEnded",
+			input: args{
+				userId: 1,
+				itemId: 1,
+				input:  notes.UpdateItemInput{Archived: boolPointer(true)},
+				next:   ended,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "standup", "", false, "i", dueAt, weekly, 5, created, created, 1, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET archived=\\$1").
+					WithArgs(true, 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Recurring Version Conflict",
+			input: args{
+				userId:  1,
+				itemId:  1,
+				input:   notes.UpdateItemInput{Archived: boolPointer(true)},
+				version: 3,
+				next:    following,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE OF ti").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(currentColumns).AddRow(1, "standup", "", false, "i", dueAt, weekly, 2, created, created, 4, 7))
+				mock.ExpectQuery("UPDATE notes_items ti SET archived=\\$1").
+					WithArgs(true, 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectRollback()
 			},
+			wantErr: notes.ErrVersionConflict,
 		},
 		{
 			name:  "OK_NoInput",
 			input: args{userId: 1, itemId: 1},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_items ti SET FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs(1, 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE notes_items ti SET updated_at=now\\(\\), version=ti.version\\+1 FROM lists_items li, users_lists ul WHERE (.+)").
+					WithArgs(1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "Version Conflict",
+			input: args{
+				userId:  1,
+				itemId:  1,
+				input:   notes.UpdateItemInput{Title: stringPointer("updated title")},
+				version: 3,
+			},
+			mock: func() {
+				mock.ExpectQuery("UPDATE notes_items ti SET (.+) AND \\(\\$4 = 0 OR ti.version = \\$4\\) RETURNING ti.id, ti.version").
+					WithArgs("updated title", 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}))
 			},
+			wantErr: notes.ErrVersionConflict,
 		},
 	}
 
@@ -399,11 +736,12 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.Update(tt.input.userId, tt.input.itemId, tt.input.input)
-			if tt.wantErr {
-				assert.Error(t, err)
+			got, err := r.Update(tt.input.userId, tt.input.itemId, tt.input.input, tt.input.version, tt.input.next)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, 2, got)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -411,6 +749,30 @@ func TestNotesItemPostgres_Update(t *testing.T) {
 	}
 }
 
+func TestNotesItemPostgres_GetRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	rows := sqlmock.NewRows([]string{"role"}).AddRow("viewer")
+	mock.ExpectQuery("SELECT ul.role FROM lists_items li INNER JOIN users_lists ul on (.+) INNER JOIN notes_items ti on (.+) INNER JOIN notes_lists tl on (.+) WHERE (.+) AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL ORDER BY array_position(.+) LIMIT 1").
+		WithArgs(3, 1).
+		WillReturnRows(rows)
+
+	got, err := r.GetRole(1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "viewer", got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func stringPointer(s string) *string {
 	return &s
 }
@@ -418,3 +780,460 @@ func stringPointer(s string) *string {
 func boolPointer(b bool) *bool {
 	return &b
 }
+
+func TestNotesItemPostgres_GetRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"item_id", "version", "title", "archived", "author", "created_at"}).
+		AddRow(1, 2, "title2", true, "alice", created).
+		AddRow(1, 1, "title1", false, nil, created)
+	mock.ExpectQuery("SELECT (.+) FROM item_revisions ir LEFT JOIN users u on (.+) WHERE ir.item_id = (.+) ORDER BY ir.version DESC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	got, err := r.GetRevisions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.ItemRevision{
+		{ItemId: 1, Version: 2, Title: "title2", Archived: true, Author: stringPointer("alice"), CreatedAt: created},
+		{ItemId: 1, Version: 1, Title: "title1", CreatedAt: created},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotesItemPostgres_GetRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"item_id", "version", "title", "description", "archived", "author", "created_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    notes.ItemRevision
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).AddRow(1, 2, "title", "description", false, "alice", created)
+				mock.ExpectQuery("SELECT (.+) FROM item_revisions ir LEFT JOIN users u on (.+) WHERE ir.item_id = (.+) AND ir.version = (.+)").
+					WithArgs(1, 2).
+					WillReturnRows(rows)
+			},
+			want: notes.ItemRevision{ItemId: 1, Version: 2, Title: "title", Description: "description", Author: stringPointer("alice"), CreatedAt: created},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM item_revisions").
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetRevision(1, 2)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesItemPostgres_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_items ti SET deleted_at=NULL FROM lists_items li, users_lists ul, notes_lists tl WHERE (.+) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL").
+					WithArgs(1, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "List In Trash",
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_items ti SET deleted_at=NULL").
+					WithArgs(1, 5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Restore(1, 5)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesItemPostgres_Move(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("5"))
+				mock.ExpectExec("UPDATE lists_items li SET list_id = \\$3 FROM users_lists ul, notes_items ti, notes_lists sl WHERE (.+) AND sl.id = li.list_id AND li.item_id = ANY\\(\\$1\\) (.+) AND sl.deleted_at IS NULL AND EXISTS \\(SELECT 1 FROM users_lists tu (.+)\\)").
+					WithArgs("{4,5}", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("UPDATE notes_items SET position=\\$1, updated_at=now\\(\\) WHERE id = \\$2").
+					WithArgs("6", 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE notes_items SET position=\\$1, updated_at=now\\(\\) WHERE id = \\$2").
+					WithArgs("7", 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Partial",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("5"))
+				mock.ExpectExec("UPDATE lists_items li SET list_id").
+					WithArgs("{4,5}", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Move(1, 2, []int{4, 5})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesItemPostgres_Copy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("5"))
+				for i, id := range []int{4, 5} {
+					mock.ExpectQuery("INSERT INTO notes_items (.+) SELECT ti.title, ti.description, ti.format, ti.archived, \\$3, ti.due_at, ti.priority, ti.done, ti.done_at, ti.remind_at, ti.reminded_at, ti.recurrence, ti.occurrence FROM notes_items ti (.+) INNER JOIN notes_lists sl on sl.id = li.list_id WHERE (.+) AND sl.deleted_at IS NULL LIMIT 1 RETURNING id").
+						WithArgs(id, 1, []string{"6", "7"}[i]).
+						WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10 + i))
+					mock.ExpectExec("INSERT INTO lists_items").
+						WithArgs(2, 10+i).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec("INSERT INTO item_revisions").
+						WithArgs(10+i, 1).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec("INSERT INTO checklist_entries").
+						WithArgs(id, 10+i, true).
+						WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectExec("INSERT INTO item_links \\(source_id, ref, target_id\\) SELECT \\$2, ref, target_id FROM item_links WHERE source_id = \\$1").
+						WithArgs(id, 10+i).
+						WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+				mock.ExpectCommit()
+			},
+			want: []int{10, 11},
+		},
+		{
+			name: "Unreadable Item",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM notes_lists WHERE id = \\$1 FOR UPDATE").WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("5"))
+				mock.ExpectQuery("INSERT INTO notes_items").
+					WithArgs(4, 1, "6").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectExec("INSERT INTO lists_items").
+					WithArgs(2, 10).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO item_revisions").
+					WithArgs(10, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO checklist_entries").
+					WithArgs(4, 10, true).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO item_links").
+					WithArgs(4, 10).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("INSERT INTO notes_items").
+					WithArgs(5, 1, "7").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Copy(1, 2, []int{4, 5})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesItemPostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	anchorId := 3
+
+	tests := []struct {
+		name    string
+		input   notes.ReorderInput
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name:  "After",
+			input: notes.ReorderInput{AfterId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT a.position, la.list_id FROM notes_items a (.+) WHERE a.id = \\$1 AND lm.item_id = \\$2 (.+) FOR UPDATE OF tl").
+					WithArgs(3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "list_id"}).AddRow("a", 1))
+				mock.ExpectQuery("SELECT COALESCE\\(min\\(ti.position\\), ''\\) FROM notes_items ti (.+) AND ti.position > \\$3").
+					WithArgs(1, 2, "a").
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("c"))
+				mock.ExpectExec("UPDATE notes_items SET position=\\$1, updated_at=now\\(\\) WHERE id = \\$2").
+					WithArgs("b", 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Before First",
+			input: notes.ReorderInput{BeforeId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT a.position, la.list_id FROM notes_items a").
+					WithArgs(3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "list_id"}).AddRow("a", 1))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(ti.position\\), ''\\) FROM notes_items ti (.+) AND ti.position < \\$3").
+					WithArgs(1, 2, "a").
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectExec("UPDATE notes_items SET position").
+					WithArgs("5", 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Anchor In Another List",
+			input: notes.ReorderInput{AfterId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT a.position, la.list_id FROM notes_items a").
+					WithArgs(3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "list_id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Reorder(1, 2, tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesItemPostgres_GetDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesItemPostgres(sqlxDb)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	due := from.Add(9 * time.Hour)
+	columns := []string{"id", "title", "due_at", "priority", "list_id"}
+
+	tests := []struct {
+		name string
+		from *time.Time
+		to   *time.Time
+		mock func()
+		want []notes.ListedItem
+	}{
+		{
+			name: "Range",
+			from: &from,
+			to:   &to,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+), li.list_id FROM notes_items ti (.+) WHERE ul.user_id = \\$1 AND ti.due_at IS NOT NULL AND NOT ti.done AND NOT ti.archived (.+) AND ti.due_at >= \\$2 AND ti.due_at < \\$3 ORDER BY ti.due_at ASC, ti.priority DESC, ti.id ASC").
+					WithArgs(1, from, to).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "title", due, notes.PriorityHigh, 7))
+			},
+			want: []notes.ListedItem{
+				{NotesItem: notes.NotesItem{Id: 4, Title: "title", DueAt: &due, Priority: notes.PriorityHigh}, ListId: 7},
+			},
+		},
+		{
+			name: "Open Start",
+			to:   &to,
+			mock: func() {
+				mock.ExpectQuery("tl.deleted_at IS NULL AND ti.due_at < \\$2 ORDER BY").
+					WithArgs(1, to).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetDue(1, tt.from, tt.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -17,14 +17,23 @@ func NewNotesListPostgres(db *sqlx.DB) *NotesListPostgres {
 	return &NotesListPostgres{db: db}
 }
 
+// Create adds the list after the other lists of the user.
 func (r *NotesListPostgres) Create(userId int, list notes.NotesList) (int, error) {
-	var id int
+	var (
+		id   int
+		last string
+	)
 
 	tx, err := r.db.Begin()
 	if err != nil {
 		return -1, err
 	}
 
+	if err := tx.QueryRow(lastListPositionQuery, userId).Scan(&last); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
 	createListQuery := fmt.Sprintf("INSERT INTO %s (title, description) VALUES ($1, $2) RETURNING id", notesListsTable)
 	row := tx.QueryRow(createListQuery, list.Title, list.Description)
 	if err := row.Scan(&id); err != nil {
@@ -32,8 +41,8 @@ func (r *NotesListPostgres) Create(userId int, list notes.NotesList) (int, error
 		return -1, err
 	}
 
-	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id) VALUES ($1, $2)", usersListsTable)
-	_, err = tx.Exec(createUsersListQuery, userId, id)
+	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, role, position) VALUES ($1, $2, '%s', $3)", usersListsTable, notes.RoleOwner)
+	_, err = tx.Exec(createUsersListQuery, userId, id, rankAfter(last))
 	if err != nil {
 		tx.Rollback()
 		return -1, err
@@ -42,24 +51,62 @@ func (r *NotesListPostgres) Create(userId int, list notes.NotesList) (int, error
 	return id, tx.Commit()
 }
 
-func (r *NotesListPostgres) GetAll(userId int) ([]notes.NotesList, error) {
+// GetAll returns one page of the user's lists and the cursor of the next
+// page, which is empty on the last one.
+func (r *NotesListPostgres) GetAll(userId int, q notes.ListsQuery) ([]notes.NotesList, string, error) {
 	var lists []notes.NotesList
 
+	p, err := newPage(q.PageQuery)
+	if err != nil {
+		return nil, "", err
+	}
+	p = p.withAlias(notes.SortPosition, "ul")
+
+	args := []interface{}{userId}
+	filter := ""
+	if q.ModifiedSince != nil {
+		args = append(args, *q.ModifiedSince)
+		filter = fmt.Sprintf(" AND tl.updated_at > $%d", len(args))
+	}
+
+	where, tail, pageArgs, err := p.clause("tl", len(args)+1)
+	if err != nil {
+		return nil, "", err
+	}
+
 	query := fmt.Sprintf(
-		"SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1",
+		"SELECT tl.id, tl.title, tl.description, ul.role, ul.position, tl.created_at, tl.updated_at, tl.version FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND tl.deleted_at IS NULL%s%s%s",
 		notesListsTable,
 		usersListsTable,
+		filter,
+		where,
+		tail,
 	)
-	err := r.db.Select(&lists, query, userId)
+	args = append(args, pageArgs...)
+
+	if err := r.db.Select(&lists, query, args...); err != nil {
+		return nil, "", err
+	}
 
-	return lists, err
+	fetched := len(lists)
+	if fetched > p.Limit {
+		lists = lists[:p.Limit]
+	}
+
+	var next string
+	if len(lists) > 0 {
+		last := lists[len(lists)-1]
+		next = p.next(fetched, last.Id, last.Title, last.Position, last.CreatedAt, last.UpdatedAt)
+	}
+
+	return lists, next, nil
 }
 
 func (r *NotesListPostgres) GetById(userId, listId int) (notes.NotesList, error) {
 	var list notes.NotesList
 
 	query := fmt.Sprintf(
-		`SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2`,
+		`SELECT tl.id, tl.title, tl.description, ul.role, ul.position, tl.created_at, tl.updated_at, tl.version FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2 AND tl.deleted_at IS NULL`,
 		notesListsTable,
 		usersListsTable,
 	)
@@ -68,19 +115,33 @@ func (r *NotesListPostgres) GetById(userId, listId int) (notes.NotesList, error)
 	return list, err
 }
 
-func (r *NotesListPostgres) Delete(userId, listId int) error {
+// Delete moves the list, and with it all of its items, to the trash. A
+// non-zero version must match the current one, otherwise
+// notes.ErrVersionConflict is returned.
+func (r *NotesListPostgres) Delete(userId, listId, version int) error {
 	query := fmt.Sprintf(
-		"DELETE FROM %s tl USING %s ul WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2",
+		"UPDATE %s tl SET deleted_at=now() FROM %s ul WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2 AND ul.role IN (%s) AND tl.deleted_at IS NULL AND ($3 = 0 OR tl.version = $3)",
 		notesListsTable,
 		usersListsTable,
+		ownerRoles,
 	)
 
-	_, err := r.db.Exec(query, userId, listId)
+	res, err := r.db.Exec(query, userId, listId, version)
+	if err != nil {
+		return err
+	}
+
+	if version != 0 {
+		return versionError(expectAffected(res), version)
+	}
 
-	return err
+	return nil
 }
 
-func (r *NotesListPostgres) Update(userId, listId int, inp notes.UpdateListInput) error {
+// Update applies inp and returns the new version of the list. A non-zero
+// version must match the current one, otherwise notes.ErrVersionConflict is
+// returned and nothing is changed.
+func (r *NotesListPostgres) Update(userId, listId int, inp notes.UpdateListInput, version int) (int, error) {
 	qValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -97,22 +158,227 @@ func (r *NotesListPostgres) Update(userId, listId int, inp notes.UpdateListInput
 		argId++
 	}
 
+	qValues = append(qValues, "updated_at=now()", "version=tl.version+1")
 	qString := strings.Join(qValues, ", ")
 
 	query := fmt.Sprintf(
-		"UPDATE %s tl SET %s FROM %s ul WHERE tl.id = ul.list_id AND ul.list_id=$%d AND ul.user_id=$%d",
+		"UPDATE %s tl SET %s FROM %s ul WHERE tl.id = ul.list_id AND ul.list_id=$%d AND ul.user_id=$%d AND ul.role IN (%s) AND tl.deleted_at IS NULL AND ($%d = 0 OR tl.version = $%d) RETURNING tl.version",
 		notesListsTable,
 		qString,
 		usersListsTable,
 		argId,
 		argId+1,
+		writeRoles,
+		argId+2,
+		argId+2,
 	)
-	args = append(args, listId, userId)
+	args = append(args, listId, userId, version)
 
 	logrus.Debugf("updateQuery: %s", query)
 	logrus.Debugf("args: %s", args)
 
-	_, err := r.db.Exec(query, args...)
+	var newVersion int
+	if err := r.db.QueryRow(query, args...).Scan(&newVersion); err != nil {
+		return -1, versionError(err, version)
+	}
+
+	return newVersion, nil
+}
+
+// Reorder places the list directly after or before another list of the
+// user. Positions of lists are per user, so this changes the order of the
+// user's lists only. The other list must be one of theirs, otherwise
+// sql.ErrNoRows is returned.
+func (r *NotesListPostgres) Reorder(userId, listId int, inp notes.ReorderInput) error {
+	var (
+		anchor   string
+		neighbor string
+	)
+
+	anchorId, agg, cmp := inp.BeforeId, "max", "<"
+	if inp.AfterId != nil {
+		anchorId, agg, cmp = inp.AfterId, "min", ">"
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Locking the user's membership of the moved list serializes their
+	// reorders, so that two of them can't pick the same free position.
+	lockQuery := fmt.Sprintf("SELECT 1 FROM %s WHERE user_id = $1 AND list_id = $2 FOR UPDATE", usersListsTable)
+	if _, err := tx.Exec(lockQuery, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	anchorQuery := fmt.Sprintf("SELECT position FROM %s WHERE user_id = $1 AND list_id = $2 AND list_id <> $3", usersListsTable)
+	if err := tx.QueryRow(anchorQuery, userId, *anchorId, listId).Scan(&anchor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	neighborQuery := fmt.Sprintf(
+		"SELECT COALESCE(%s(position), '') FROM %s WHERE user_id = $1 AND list_id <> $2 AND position %s $3",
+		agg,
+		usersListsTable,
+		cmp,
+	)
+	if err := tx.QueryRow(neighborQuery, userId, listId, anchor).Scan(&neighbor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position := rankBetween(neighbor, anchor)
+	if inp.AfterId != nil {
+		position = rankBetween(anchor, neighbor)
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET position=$1 WHERE user_id = $2 AND list_id = $3", usersListsTable)
+	res, err := tx.Exec(updateQuery, position, userId, listId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := expectAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restore takes the list out of the trash. Only owners can restore a
+// list; sql.ErrNoRows is returned when the user isn't one or the list is
+// not in the trash.
+func (r *NotesListPostgres) Restore(userId, listId int) error {
+	query := fmt.Sprintf(
+		"UPDATE %s tl SET deleted_at=NULL FROM %s ul WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2 AND ul.role IN (%s) AND tl.deleted_at IS NOT NULL",
+		notesListsTable,
+		usersListsTable,
+		ownerRoles,
+	)
+
+	res, err := r.db.Exec(query, userId, listId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// GetRole returns the role the user holds on the list. Lists in the trash
+// report sql.ErrNoRows like lists the user is no member of.
+func (r *NotesListPostgres) GetRole(userId, listId int) (string, error) {
+	var role string
+
+	query := fmt.Sprintf(
+		"SELECT ul.role FROM %s ul INNER JOIN %s tl on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2 AND tl.deleted_at IS NULL",
+		usersListsTable,
+		notesListsTable,
+	)
+	err := r.db.Get(&role, query, userId, listId)
+
+	return role, err
+}
+
+func (r *NotesListPostgres) GetMembers(listId int) ([]notes.ListMember, error) {
+	var members []notes.ListMember
+
+	query := fmt.Sprintf(
+		"SELECT u.username, u.name, ul.role FROM %s ul INNER JOIN %s u on u.id = ul.user_id WHERE ul.list_id = $1 ORDER BY u.username",
+		usersListsTable,
+		usersTable,
+	)
+	err := r.db.Select(&members, query, listId)
+
+	return members, err
+}
+
+// Share grants username the role on the list, or changes the role they
+// already hold. The caller must be an owner of the list and cannot change
+// their own membership this way; sql.ErrNoRows is returned when either check
+// or the username lookup fails.
+func (r *NotesListPostgres) Share(userId, listId int, username, role string) error {
+	var (
+		memberId int
+		last     string
+	)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	memberQuery := fmt.Sprintf(
+		`SELECT u.id FROM %[2]s u INNER JOIN %[1]s ul on ul.list_id = $2 AND ul.user_id = $1 AND ul.role IN (%[3]s)
+		WHERE u.username = $3 AND u.id <> $1`,
+		usersListsTable,
+		usersTable,
+		ownerRoles,
+	)
+	if err := tx.QueryRow(memberQuery, userId, listId, username).Scan(&memberId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.QueryRow(lastListPositionQuery, memberId).Scan(&last); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	shareQuery := fmt.Sprintf(
+		`INSERT INTO %s (user_id, list_id, role, position) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, list_id) DO UPDATE SET role = EXCLUDED.role`,
+		usersListsTable,
+	)
+	if _, err := tx.Exec(shareQuery, memberId, listId, role, rankAfter(last)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unshare removes username from the list. The last owner of a list cannot
+// be removed, so every list keeps someone able to manage it. The owners are
+// locked before they are counted, so two owners removing each other at the
+// same time can't both succeed.
+func (r *NotesListPostgres) Unshare(userId, listId int, username string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	lockQuery := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = $1 AND role = '%s' FOR UPDATE", usersListsTable, notes.RoleOwner)
+	if _, err := tx.Exec(lockQuery, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`DELETE FROM %[1]s ul USING %[2]s u, %[1]s ol
+		WHERE ul.user_id = u.id AND u.username = $3 AND ul.list_id = $2
+		AND ol.list_id = ul.list_id AND ol.user_id = $1 AND ol.role IN (%[3]s)
+		AND (ul.role <> '%[4]s' OR (SELECT count(*) FROM %[1]s WHERE list_id = $2 AND role = '%[4]s') > 1)`,
+		usersListsTable,
+		usersTable,
+		ownerRoles,
+		notes.RoleOwner,
+	)
+
+	res, err := tx.Exec(query, userId, listId, username)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := expectAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
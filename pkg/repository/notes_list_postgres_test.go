@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Liopun/notes-app"
@@ -46,10 +47,13 @@ func TestNotesListPostgres_Create(t *testing.T) {
 			mock: func() {
 				mock.ExpectBegin()
 
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM users_lists WHERE user_id = \\$1").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("0000000003"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
 
 				mock.ExpectQuery("INSERT INTO notes_lists").WithArgs("test title", "test description").WillReturnRows(rows)
-				mock.ExpectExec("INSERT INTO users_lists").WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO users_lists (.+) VALUES (.+'owner', \\$3)").WithArgs(1, 1, "0000000004").WillReturnResult(sqlmock.NewResult(1, 1))
 
 				mock.ExpectCommit()
 			},
@@ -67,6 +71,9 @@ func TestNotesListPostgres_Create(t *testing.T) {
 			mock: func() {
 				mock.ExpectBegin()
 
+				mock.ExpectQuery("SELECT COALESCE").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+
 				rows := sqlmock.NewRows([]string{"id"})
 				mock.ExpectQuery("INSERT INTO notes_lists").WithArgs("", "").WillReturnRows(rows)
 
@@ -105,15 +112,20 @@ func TestNotesListPostgres_GetAll(t *testing.T) {
 
 	r := NewNotesListPostgres(sqlxDb)
 
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "title", "description", "role", "created_at", "updated_at"}
+
 	type args struct {
 		userId int
+		query  notes.ListsQuery
 	}
 	tests := []struct {
-		name    string
-		input   args
-		mock    func()
-		want    []notes.NotesList
-		wantErr bool
+		name     string
+		input    args
+		mock     func()
+		want     []notes.NotesList
+		wantNext bool
+		wantErr  bool
 	}{
 		{
 			name: "OK",
@@ -121,18 +133,69 @@ func TestNotesListPostgres_GetAll(t *testing.T) {
 				userId: 1,
 			},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
-					AddRow(1, "title1", "description1").
-					AddRow(2, "title2", "description2").
-					AddRow(3, "title3", "description3")
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "title1", "description1", "owner", created, created).
+					AddRow(2, "title2", "description2", "editor", created, created).
+					AddRow(3, "title3", "description3", "viewer", created, created)
+
+				mock.ExpectQuery("SELECT (.+) FROM notes_lists tl INNER JOIN users_lists ul on (.+) WHERE ul.user_id = \\$1 AND tl.deleted_at IS NULL ORDER BY ul.position ASC, tl.id ASC LIMIT \\$2").
+					WithArgs(1, notes.DefaultPageLimit+1).
+					WillReturnRows(rows)
+			},
+			want: []notes.NotesList{
+				{Id: 1, Title: "title1", Description: "description1", Role: "owner", CreatedAt: created, UpdatedAt: created},
+				{Id: 2, Title: "title2", Description: "description2", Role: "editor", CreatedAt: created, UpdatedAt: created},
+				{Id: 3, Title: "title3", Description: "description3", Role: "viewer", CreatedAt: created, UpdatedAt: created},
+			},
+		},
+		{
+			name: "Has Next Page",
+			input: args{
+				userId: 1,
+				query:  notes.ListsQuery{PageQuery: notes.PageQuery{Limit: 2, Sort: notes.SortTitle, Order: notes.OrderDesc}},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(3, "title3", "description3", "viewer", created, created).
+					AddRow(2, "title2", "description2", "editor", created, created).
+					AddRow(1, "title1", "description1", "owner", created, created)
+
+				mock.ExpectQuery("ORDER BY tl.title DESC, tl.id DESC LIMIT \\$2").
+					WithArgs(1, 3).
+					WillReturnRows(rows)
+			},
+			want: []notes.NotesList{
+				{Id: 3, Title: "title3", Description: "description3", Role: "viewer", CreatedAt: created, UpdatedAt: created},
+				{Id: 2, Title: "title2", Description: "description2", Role: "editor", CreatedAt: created, UpdatedAt: created},
+			},
+			wantNext: true,
+		},
+		{
+			name: "Modified Since",
+			input: args{
+				userId: 1,
+				query:  notes.ListsQuery{ModifiedSince: &created, PageQuery: notes.PageQuery{Sort: notes.SortUpdated}},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "title2", "description2", "editor", created, created.Add(time.Hour))
 
-				mock.ExpectQuery("SELECT (.+) FROM notes_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery("WHERE ul.user_id = \\$1 AND tl.deleted_at IS NULL AND tl.updated_at > \\$2 ORDER BY tl.updated_at ASC, tl.id ASC LIMIT \\$3").
+					WithArgs(1, created, notes.DefaultPageLimit+1).
+					WillReturnRows(rows)
 			},
 			want: []notes.NotesList{
-				{Id: 1, Title: "title1", Description: "description1"},
-				{Id: 2, Title: "title2", Description: "description2"},
-				{Id: 3, Title: "title3", Description: "description3"},
+				{Id: 2, Title: "title2", Description: "description2", Role: "editor", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+			},
+		},
+		{
+			name: "Invalid Cursor",
+			input: args{
+				userId: 1,
+				query:  notes.ListsQuery{PageQuery: notes.PageQuery{Cursor: "not a cursor"}},
 			},
+			mock:    func() {},
+			wantErr: true,
 		},
 	}
 
@@ -140,12 +203,13 @@ func TestNotesListPostgres_GetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetAll(tt.input.userId)
+			got, next, err := r.GetAll(tt.input.userId, tt.input.query)
 			if tt.wantErr {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, notes.ErrInvalidCursor)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.wantNext, next != "")
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -153,6 +217,49 @@ func TestNotesListPostgres_GetAll(t *testing.T) {
 	}
 }
 
+func TestNotesListPostgres_GetAll_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC)
+	columns := []string{"id", "title", "description", "role", "created_at", "updated_at"}
+	query := notes.ListsQuery{PageQuery: notes.PageQuery{Limit: 1, Sort: notes.SortCreated}}
+
+	mock.ExpectQuery("ORDER BY tl.created_at ASC").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "title1", "", "owner", created, created).
+			AddRow(2, "title2", "", "owner", created, created))
+
+	_, next, err := r.GetAll(1, query)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("WHERE ul.user_id = \\$1 AND tl.deleted_at IS NULL AND \\(tl.created_at, tl.id\\) > \\(\\$2, \\$3\\) ORDER BY tl.created_at ASC, tl.id ASC LIMIT \\$4").
+		WithArgs(1, created, 1, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "title2", "", "owner", created, created))
+
+	query.Cursor = next
+	got, next, err := r.GetAll(1, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got[0].Id)
+	assert.Empty(t, next)
+
+	// A cursor is tied to the sort it was issued for.
+	query.Sort = notes.SortTitle
+	_, _, err = r.GetAll(1, query)
+	assert.ErrorIs(t, err, notes.ErrInvalidCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestNotesListPostgres_GetById(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -235,8 +342,9 @@ func TestNotesListPostgres_Delete(t *testing.T) {
 	r := NewNotesListPostgres(sqlxDb)
 
 	type args struct {
-		listId int
-		userId int
+		listId  int
+		userId  int
+		version int
 	}
 
 	tests := []struct {
@@ -252,8 +360,8 @@ func TestNotesListPostgres_Delete(t *testing.T) {
 				listId: 1,
 			},
 			mock: func() {
-				mock.ExpectExec("DELETE FROM notes_lists tl USING users_lists ul WHERE (.+)").
-					WithArgs(1, 1).
+				mock.ExpectExec("UPDATE notes_lists tl SET deleted_at=now\\(\\) FROM users_lists ul WHERE (.+) AND ul.role IN (.+'owner'.+) AND tl.deleted_at IS NULL").
+					WithArgs(1, 1, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -264,19 +372,29 @@ func TestNotesListPostgres_Delete(t *testing.T) {
 				listId: -1,
 			},
 			mock: func() {
-				mock.ExpectExec("DELETE FROM notes_lists tl USING users_lists ul WHERE (.+)").
-					WithArgs(-1, -1).
+				mock.ExpectExec("UPDATE notes_lists tl SET deleted_at=now\\(\\) FROM users_lists ul WHERE (.+)").
+					WithArgs(-1, -1, 0).
 					WillReturnError(sql.ErrNoRows)
 			},
 			wantErr: true,
 		},
+		{
+			name:  "Version Conflict",
+			input: args{userId: 1, listId: 1, version: 3},
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_lists tl SET deleted_at(.+) AND \\(\\$3 = 0 OR tl.version = \\$3\\)").
+					WithArgs(1, 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.Delete(tt.input.userId, tt.input.listId)
+			err := r.Delete(tt.input.userId, tt.input.listId, tt.input.version)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -301,16 +419,17 @@ func TestNotesListPostgres_Update(t *testing.T) {
 	r := NewNotesListPostgres(sqlxDb)
 
 	type args struct {
-		userId int
-		listId int
-		input  notes.UpdateListInput
+		userId  int
+		listId  int
+		input   notes.UpdateListInput
+		version int
 	}
 
 	tests := []struct {
 		name    string
 		input   args
 		mock    func()
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "OK",
@@ -323,9 +442,9 @@ func TestNotesListPostgres_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("updated title", "updated descr", 1, 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
+					WithArgs("updated title", "updated descr", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
 			},
 		},
 		{
@@ -338,9 +457,9 @@ func TestNotesListPostgres_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("updated title", 1, 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
+					WithArgs("updated title", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
 			},
 		},
 		{
@@ -353,9 +472,9 @@ func TestNotesListPostgres_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("updated desc", 1, 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE notes_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
+					WithArgs("updated desc", 1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
 			},
 		},
 		{
@@ -365,10 +484,25 @@ func TestNotesListPostgres_Update(t *testing.T) {
 				listId: 1,
 			},
 			mock: func() {
-				mock.ExpectExec("UPDATE notes_lists tl SET FROM users_lists ul WHERE (.+)").
-					WithArgs(1, 1).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE notes_lists tl SET updated_at=now\\(\\), version=tl.version\\+1 FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+			},
+		},
+		{
+			name: "Version Conflict",
+			input: args{
+				userId:  1,
+				listId:  1,
+				input:   notes.UpdateListInput{Title: stringPointer("updated title")},
+				version: 3,
+			},
+			mock: func() {
+				mock.ExpectQuery("UPDATE notes_lists tl SET (.+) AND \\(\\$4 = 0 OR tl.version = \\$4\\) RETURNING tl.version").
+					WithArgs("updated title", 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}))
 			},
+			wantErr: notes.ErrVersionConflict,
 		},
 	}
 
@@ -376,9 +510,365 @@ func TestNotesListPostgres_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.Update(tt.input.userId, tt.input.listId, tt.input.input)
+			got, err := r.Update(tt.input.userId, tt.input.listId, tt.input.input, tt.input.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesListPostgres_GetRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"role"}).AddRow("editor")
+				mock.ExpectQuery("SELECT ul.role FROM users_lists ul INNER JOIN notes_lists tl on (.+) WHERE (.+) AND tl.deleted_at IS NULL").WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: "editor",
+		},
+		{
+			name: "Not A Member",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"role"})
+				mock.ExpectQuery("SELECT ul.role FROM users_lists ul INNER JOIN notes_lists tl on (.+) WHERE (.+) AND tl.deleted_at IS NULL").WithArgs(1, 2).WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetRole(1, 2)
 			if tt.wantErr {
 				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesListPostgres_GetMembers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	rows := sqlmock.NewRows([]string{"username", "name", "role"}).
+		AddRow("alice", "Alice", "owner").
+		AddRow("bob", "Bob", "viewer")
+	mock.ExpectQuery("SELECT (.+) FROM users_lists ul INNER JOIN users u on (.+) WHERE ul.list_id = (.+)").WithArgs(1).WillReturnRows(rows)
+
+	got, err := r.GetMembers(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.ListMember{
+		{Username: "alice", Name: "Alice", Role: "owner"},
+		{Username: "bob", Name: "Bob", Role: "viewer"},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotesListPostgres_Share(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT u.id FROM users u INNER JOIN users_lists ul on (.+) WHERE u.username = \\$3 AND u.id <> \\$1").
+					WithArgs(1, 2, "bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM users_lists WHERE user_id = \\$1").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("i0001"))
+				mock.ExpectExec("INSERT INTO users_lists (.+) VALUES (.+) ON CONFLICT (.+) DO UPDATE SET role").
+					WithArgs(3, 2, "viewer", "i0002").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Owner Or Unknown User",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT u.id FROM users u").
+					WithArgs(1, 2, "bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Share(1, 2, "bob", "viewer")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesListPostgres_Unshare(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT user_id FROM users_lists WHERE list_id = (.+) AND role = 'owner' FOR UPDATE").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM users_lists ul USING users u, users_lists ol WHERE (.+)").
+					WithArgs(1, 2, "bob").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Last Owner",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT user_id FROM users_lists WHERE list_id = (.+) AND role = 'owner' FOR UPDATE").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM users_lists ul USING users u, users_lists ol WHERE (.+)").
+					WithArgs(1, 2, "bob").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Unshare(1, 2, "bob")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesListPostgres_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_lists tl SET deleted_at=NULL FROM users_lists ul WHERE (.+) AND ul.role IN (.+'owner'.+) AND tl.deleted_at IS NOT NULL").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not In Trash",
+			mock: func() {
+				mock.ExpectExec("UPDATE notes_lists tl SET deleted_at=NULL").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Restore(1, 2)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotesListPostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewNotesListPostgres(sqlxDb)
+
+	anchorId := 3
+
+	tests := []struct {
+		name    string
+		input   notes.ReorderInput
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name:  "Before",
+			input: notes.ReorderInput{BeforeId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT 1 FROM users_lists WHERE user_id = \\$1 AND list_id = \\$2 FOR UPDATE").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT position FROM users_lists WHERE user_id = \\$1 AND list_id = \\$2 AND list_id <> \\$3").
+					WithArgs(1, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("0000000002"))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM users_lists WHERE (.+) AND position < \\$3").
+					WithArgs(1, 2, "0000000002").
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("0000000001"))
+				mock.ExpectExec("UPDATE users_lists SET position=\\$1 WHERE user_id = \\$2 AND list_id = \\$3").
+					WithArgs("0000000001i", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "After Last",
+			input: notes.ReorderInput{AfterId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT 1 FROM users_lists").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT position FROM users_lists").
+					WithArgs(1, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("b"))
+				mock.ExpectQuery("SELECT COALESCE\\(min\\(position\\), ''\\) FROM users_lists WHERE (.+) AND position > \\$3").
+					WithArgs(1, 2, "b").
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectExec("UPDATE users_lists SET position").
+					WithArgs("c", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Not A Member",
+			input: notes.ReorderInput{AfterId: &anchorId},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT 1 FROM users_lists").
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT position FROM users_lists").
+					WithArgs(1, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("b"))
+				mock.ExpectQuery("SELECT COALESCE").
+					WithArgs(1, 2, "b").
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(""))
+				mock.ExpectExec("UPDATE users_lists SET position").
+					WithArgs("c", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Reorder(1, 2, tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Liopun/notes-app"
+)
+
+var sortColumns = map[string]string{
+	notes.SortPosition: "position",
+	notes.SortCreated:  "created_at",
+	notes.SortUpdated:  "updated_at",
+	notes.SortTitle:    "title",
+}
+
+// cursor is the position after the last row of a page: the sort value and
+// id of that row. It is handed to clients as opaque base64 JSON.
+type cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	Id    int    `json:"i"`
+}
+
+// page is a PageQuery with its defaults applied.
+type page struct {
+	notes.PageQuery
+	after   *cursor
+	aliases map[string]string
+}
+
+func newPage(q notes.PageQuery) (page, error) {
+	if q.Limit == 0 {
+		q.Limit = notes.DefaultPageLimit
+	}
+
+	if q.Sort == "" {
+		q.Sort = notes.SortPosition
+	}
+
+	if q.Order == "" {
+		q.Order = notes.OrderAsc
+	}
+
+	p := page{PageQuery: q}
+	if q.Cursor == "" {
+		return p, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return p, notes.ErrInvalidCursor
+	}
+
+	p.after = &cursor{}
+	if err := json.Unmarshal(raw, p.after); err != nil {
+		return p, notes.ErrInvalidCursor
+	}
+
+	if p.after.Sort != q.Sort || p.after.Order != q.Order {
+		return p, notes.ErrInvalidCursor
+	}
+
+	return p, nil
+}
+
+// withAlias takes the column of the given sort from the table aliased as
+// alias rather than from the paged one, for sort keys kept on a joined
+// table such as the per-user position of a list.
+func (p page) withAlias(sort, alias string) page {
+	p.aliases = map[string]string{sort: alias}
+
+	return p
+}
+
+// clause returns the keyset condition (empty on the first page) and the
+// ORDER BY ... LIMIT tail for a query on the table aliased as alias, with
+// placeholders numbered from argId. One row more than the limit is fetched
+// so that the caller can tell whether there is a next page.
+func (p page) clause(alias string, argId int) (string, string, []interface{}, error) {
+	columnAlias, ok := p.aliases[p.Sort]
+	if !ok {
+		columnAlias = alias
+	}
+
+	column := fmt.Sprintf("%s.%s", columnAlias, sortColumns[p.Sort])
+	direction, cmp := "ASC", ">"
+	if p.Order == notes.OrderDesc {
+		direction, cmp = "DESC", "<"
+	}
+
+	var (
+		where string
+		args  []interface{}
+	)
+
+	if p.after != nil {
+		var value interface{} = p.after.Value
+		if p.Sort == notes.SortCreated || p.Sort == notes.SortUpdated {
+			t, err := time.Parse(time.RFC3339Nano, p.after.Value)
+			if err != nil {
+				return "", "", nil, notes.ErrInvalidCursor
+			}
+			value = t
+		}
+
+		where = fmt.Sprintf(" AND (%s, %s.id) %s ($%d, $%d)", column, alias, cmp, argId, argId+1)
+		args = append(args, value, p.after.Id)
+		argId += 2
+	}
+
+	tail := fmt.Sprintf(" ORDER BY %s %s, %s.id %s LIMIT $%d", column, direction, alias, direction, argId)
+	args = append(args, p.Limit+1)
+
+	return where, tail, args, nil
+}
+
+// next returns the cursor for the page after the current one, given the
+// number of rows fetched and the last row that is returned to the caller,
+// or an empty string when there is no next page.
+func (p page) next(fetched, id int, title, position string, createdAt, updatedAt time.Time) string {
+	if fetched <= p.Limit {
+		return ""
+	}
+
+	c := cursor{Sort: p.Sort, Order: p.Order, Id: id}
+	switch p.Sort {
+	case notes.SortCreated:
+		c.Value = createdAt.Format(time.RFC3339Nano)
+	case notes.SortUpdated:
+		c.Value = updatedAt.Format(time.RFC3339Nano)
+	case notes.SortPosition:
+		c.Value = position
+	default:
+		c.Value = title
+	}
+
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/Liopun/notes-app"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
@@ -12,6 +16,34 @@ const (
 	usersListsTable = "users_lists"
 	notesItemsTable = "notes_items"
 	listsItemsTable = "lists_items"
+
+	itemRevisionsTable = "item_revisions"
+
+	listInvitationsTable = "list_invitations"
+
+	tagsTable     = "tags"
+	itemTagsTable = "item_tags"
+
+	reminderDeliveriesTable = "reminder_deliveries"
+	inboxMessagesTable      = "inbox_messages"
+	checklistEntriesTable   = "checklist_entries"
+	foldersTable            = "folders"
+	itemLinksTable          = "item_links"
+
+	refreshTokensTable  = "refresh_tokens"
+	revokedTokensTable  = "revoked_tokens"
+	personalTokensTable = "personal_access_tokens"
+)
+
+// Role filters for WHERE clauses on users_lists, so that a query only
+// touches lists the user holds a sufficient role on.
+var (
+	writeRoles = fmt.Sprintf("'%s', '%s'", notes.RoleOwner, notes.RoleEditor)
+	ownerRoles = fmt.Sprintf("'%s'", notes.RoleOwner)
+
+	// roleOrder ranks roles from most to least privileged for
+	// array_position comparisons.
+	roleOrder = fmt.Sprintf("ARRAY['%s', '%s', '%s']::varchar[]", notes.RoleOwner, notes.RoleEditor, notes.RoleViewer)
 )
 
 type Config struct {
@@ -44,3 +76,45 @@ func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// expectAffected turns an update that matched no rows into sql.ErrNoRows.
+func expectAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// versionError reports a conditional write that matched no rows as a
+// version conflict when a version was expected. The callers have checked
+// that the row exists and is writable, so a mismatched version is the only
+// remaining reason.
+func versionError(err error, version int) error {
+	if version != 0 && errors.Is(err, sql.ErrNoRows) {
+		return notes.ErrVersionConflict
+	}
+
+	return err
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+// value of a unique column or index.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isUndefinedTable reports whether err is Postgres rejecting a query on a
+// table that doesn't exist.
+func isUndefinedTable(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == "42P01"
+}
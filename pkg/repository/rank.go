@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rankDigits are the digits of positions, in byte order. Positions are
+// compared byte by byte (the columns use the "C" collation), so any two of
+// them have room for another one in between.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// rankStart is the position of the first row of an empty order.
+const rankStart = "i0001"
+
+// lastListPositionQuery returns the greatest position among the lists of
+// user $1.
+var lastListPositionQuery = fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE user_id = $1", usersListsTable)
+
+// rankBetween returns a position that sorts strictly between a and b. An
+// empty a stands for the start and an empty b for the end of the order.
+func rankBetween(a, b string) string {
+	if b == "" || a >= b {
+		return rankAfter(a)
+	}
+
+	var rank []byte
+	bounded := true
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(a) {
+			lo = strings.IndexByte(rankDigits, a[i])
+		}
+
+		hi := len(rankDigits)
+		if bounded && i < len(b) {
+			hi = strings.IndexByte(rankDigits, b[i])
+		}
+
+		if hi-lo > 1 {
+			return string(append(rank, rankDigits[(lo+hi)/2]))
+		}
+
+		// No digit fits between lo and hi here: keep lo and look one digit
+		// further, where only a still bounds the result once lo < hi.
+		rank = append(rank, rankDigits[lo])
+		if lo < hi {
+			bounded = false
+		}
+	}
+}
+
+// rankAfter returns a position after a. It counts up in fixed width: the
+// last digit is incremented and carries into the ones before it, so that a
+// position only grows once it is all "z". Digits that carry restart at "1"
+// rather than "0", so that no position ends in "0" and rankBetween always
+// finds room before it.
+func rankAfter(a string) string {
+	rank := []byte(a)
+	for i := len(rank) - 1; i >= 0; i-- {
+		if d := strings.IndexByte(rankDigits, rank[i]); d < len(rankDigits)-1 {
+			rank[i] = rankDigits[d+1]
+			return string(rank)
+		}
+
+		rank[i] = rankDigits[1]
+	}
+
+	return a + rankStart
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "Empty", want: "i0001"},
+		{name: "Start", b: "i", want: "9"},
+		{name: "End", a: "i", want: "j"},
+		{name: "End Of Digits", a: "zz", want: "zzi0001"},
+		{name: "Carry", a: "i00zz", want: "i0111"},
+		{name: "Before Carried", a: "i00zz", b: "i0111", want: "i00zzi"},
+		{name: "Backfilled", a: "0000000001", want: "0000000002"},
+		{name: "Adjacent", a: "a", b: "b", want: "ai"},
+		{name: "Prefix", a: "a", b: "a1", want: "a0i"},
+		{name: "Gap", a: "a", b: "c", want: "b"},
+		{name: "Between Backfilled", a: "0000000009", b: "0000000010", want: "000000000m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankBetween(tt.a, tt.b)
+			assert.Equal(t, tt.want, got)
+			assert.Greater(t, got, tt.a)
+			if tt.b != "" {
+				assert.Less(t, got, tt.b)
+			}
+		})
+	}
+}
+
+func TestRankBetween_Repeated(t *testing.T) {
+	lo, hi := "a", "b"
+	for i := 0; i < 200; i++ {
+		mid := rankBetween(lo, hi)
+		assert.Greater(t, mid, lo)
+		assert.Less(t, mid, hi)
+
+		if i%2 == 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+}
+
+func TestRankAfter_Repeated(t *testing.T) {
+	last := ""
+	for i := 0; i < 10000; i++ {
+		next := rankAfter(last)
+		assert.Greater(t, next, last)
+		assert.Len(t, next, len(rankStart))
+		assert.NotEqual(t, byte('0'), next[len(next)-1])
+
+		last = next
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReminderPostgres struct {
+	db *sqlx.DB
+}
+
+func NewReminderPostgres(db *sqlx.DB) *ReminderPostgres {
+	return &ReminderPostgres{db: db}
+}
+
+// Enqueue turns up to limit items whose remind_at has passed into pending
+// deliveries, one for every member of the item's list and every channel,
+// and returns how many deliveries were created. Items are marked as
+// reminded by the same statement, and rows locked by another server are
+// skipped, so that every reminder is enqueued exactly once.
+func (r *ReminderPostgres) Enqueue(channels []string, limit int) (int64, error) {
+	query := fmt.Sprintf(
+		`WITH due AS (
+			SELECT ti.id FROM %[1]s ti INNER JOIN %[2]s li on li.item_id = ti.id INNER JOIN %[3]s tl on tl.id = li.list_id
+			WHERE ti.remind_at <= now() AND ti.reminded_at IS NULL AND NOT ti.done AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+			ORDER BY ti.remind_at LIMIT $2 FOR UPDATE OF ti SKIP LOCKED
+		), reminded AS (
+			UPDATE %[1]s ti SET reminded_at=now() FROM due WHERE ti.id = due.id RETURNING ti.id, ti.remind_at
+		)
+		INSERT INTO %[4]s (item_id, user_id, channel, remind_at)
+		SELECT reminded.id, ul.user_id, ch.channel, reminded.remind_at
+		FROM reminded INNER JOIN %[2]s li on li.item_id = reminded.id INNER JOIN %[5]s ul on ul.list_id = li.list_id
+		CROSS JOIN unnest($1::varchar[]) AS ch(channel)`,
+		notesItemsTable,
+		listsItemsTable,
+		notesListsTable,
+		reminderDeliveriesTable,
+		usersListsTable,
+	)
+
+	res, err := r.db.Exec(query, pq.Array(channels), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Claim picks up to limit pending deliveries that are due for an attempt
+// and counts the attempt. A claimed delivery is hidden from other claims
+// for the lease, after which it is picked up again should its sender have
+// died before reporting back. Deliveries whose item has since been trashed
+// or marked done are cancelled by the same statement instead of claimed.
+func (r *ReminderPostgres) Claim(limit int, lease time.Duration) ([]notes.Reminder, error) {
+	var reminders []notes.Reminder
+
+	query := fmt.Sprintf(
+		`WITH due AS (
+			SELECT rd.id, ti.done OR ti.deleted_at IS NOT NULL OR tl.deleted_at IS NOT NULL AS stale
+			FROM %[1]s rd INNER JOIN %[4]s ti on ti.id = rd.item_id INNER JOIN %[5]s li on li.item_id = rd.item_id INNER JOIN %[6]s tl on tl.id = li.list_id
+			WHERE rd.status = '%[2]s' AND rd.next_attempt_at <= now()
+			ORDER BY rd.next_attempt_at LIMIT $1 FOR UPDATE OF rd SKIP LOCKED
+		), cancelled AS (
+			UPDATE %[1]s rd SET status='%[3]s' FROM due WHERE rd.id = due.id AND due.stale
+		)
+		UPDATE %[1]s rd SET attempts=rd.attempts+1, next_attempt_at=now() + $2 * interval '1 second'
+		FROM due, %[4]s ti, %[5]s li, %[7]s u
+		WHERE rd.id = due.id AND NOT due.stale AND ti.id = rd.item_id AND li.item_id = rd.item_id AND u.id = rd.user_id
+		RETURNING rd.id, rd.channel, rd.attempts, rd.item_id, li.list_id, ti.title, ti.description, ti.due_at, rd.remind_at, rd.user_id, u.username, u.name, u.email`,
+		reminderDeliveriesTable,
+		notes.DeliveryPending,
+		notes.DeliveryCancelled,
+		notesItemsTable,
+		listsItemsTable,
+		notesListsTable,
+		usersTable,
+	)
+	err := r.db.Select(&reminders, query, limit, lease.Seconds())
+
+	return reminders, err
+}
+
+func (r *ReminderPostgres) MarkSent(deliveryId int) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status='%s', delivered_at=now(), last_error=NULL WHERE id = $1",
+		reminderDeliveriesTable,
+		notes.DeliverySent,
+	)
+	_, err := r.db.Exec(query, deliveryId)
+
+	return err
+}
+
+// MarkFailed gives up on the delivery, recording why.
+func (r *ReminderPostgres) MarkFailed(deliveryId int, reason string) error {
+	query := fmt.Sprintf("UPDATE %s SET status='%s', last_error=$2 WHERE id = $1", reminderDeliveriesTable, notes.DeliveryFailed)
+	_, err := r.db.Exec(query, deliveryId, reason)
+
+	return err
+}
+
+// Retry records why the delivery failed and schedules its next attempt.
+func (r *ReminderPostgres) Retry(deliveryId int, reason string, at time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET last_error=$2, next_attempt_at=$3 WHERE id = $1", reminderDeliveriesTable)
+	_, err := r.db.Exec(query, deliveryId, reason, at)
+
+	return err
+}
+
+// GetDeliveries returns the reminders of the item addressed to the user,
+// newest first.
+func (r *ReminderPostgres) GetDeliveries(userId, itemId int) ([]notes.ReminderDelivery, error) {
+	var deliveries []notes.ReminderDelivery
+
+	query := fmt.Sprintf(
+		`SELECT id, item_id, channel, remind_at, status, attempts, last_error, next_attempt_at, delivered_at FROM %s
+		WHERE user_id = $1 AND item_id = $2 ORDER BY remind_at DESC, id`,
+		reminderDeliveriesTable,
+	)
+	err := r.db.Select(&deliveries, query, userId, itemId)
+
+	return deliveries, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminderPostgres_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewReminderPostgres(sqlxDb)
+
+	mock.ExpectExec("WITH due AS \\( SELECT ti.id FROM notes_items ti (.+) WHERE ti.remind_at <= now\\(\\) AND ti.reminded_at IS NULL (.+) LIMIT \\$2 FOR UPDATE OF ti SKIP LOCKED \\), reminded AS \\( UPDATE notes_items ti SET reminded_at=now\\(\\) (.+) \\) INSERT INTO reminder_deliveries (.+) CROSS JOIN unnest\\(\\$1::varchar\\[\\]\\) AS ch\\(channel\\)").
+		WithArgs(`{"email","inbox"}`, 100).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	got, err := r.Enqueue([]string{notes.ChannelEmail, notes.ChannelInbox}, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReminderPostgres_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewReminderPostgres(sqlxDb)
+
+	remindAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	email := "alice@example.com"
+
+	rows := sqlmock.NewRows([]string{"id", "channel", "attempts", "item_id", "list_id", "title", "description", "due_at", "remind_at", "user_id", "username", "name", "email"}).
+		AddRow(3, notes.ChannelEmail, 1, 5, 2, "title", "description", nil, remindAt, 1, "alice", "Alice", email)
+
+	mock.ExpectQuery("WITH due AS \\( SELECT rd.id, ti.done OR ti.deleted_at IS NOT NULL OR tl.deleted_at IS NOT NULL AS stale FROM reminder_deliveries rd (.+) WHERE rd.status = 'pending' AND rd.next_attempt_at <= now\\(\\) ORDER BY rd.next_attempt_at LIMIT \\$1 FOR UPDATE OF rd SKIP LOCKED \\), "+
+		"cancelled AS \\( UPDATE reminder_deliveries rd SET status='cancelled' FROM due WHERE rd.id = due.id AND due.stale \\) "+
+		"UPDATE reminder_deliveries rd SET attempts=rd.attempts\\+1, next_attempt_at=now\\(\\) \\+ \\$2 \\* interval '1 second' FROM due, (.+) WHERE rd.id = due.id AND NOT due.stale (.+) RETURNING (.+)").
+		WithArgs(50, float64(20)).
+		WillReturnRows(rows)
+
+	got, err := r.Claim(50, 20*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.Reminder{
+		{DeliveryId: 3, Channel: notes.ChannelEmail, Attempts: 1, ItemId: 5, ListId: 2, Title: "title", Description: "description", RemindAt: remindAt, UserId: 1, Username: "alice", Name: "Alice", Email: &email},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReminderPostgres_Outcomes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewReminderPostgres(sqlxDb)
+
+	retryAt := time.Date(2026, 10, 1, 9, 1, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE reminder_deliveries SET status='sent', delivered_at=now\\(\\), last_error=NULL WHERE id = \\$1").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE reminder_deliveries SET status='failed', last_error=\\$2 WHERE id = \\$1").
+		WithArgs(4, "no email").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE reminder_deliveries SET last_error=\\$2, next_attempt_at=\\$3 WHERE id = \\$1").
+		WithArgs(5, "timeout", retryAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.MarkSent(3))
+	assert.NoError(t, r.MarkFailed(4, "no email"))
+	assert.NoError(t, r.Retry(5, "timeout", retryAt))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
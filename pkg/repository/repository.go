@@ -1,35 +1,145 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Liopun/notes-app"
 	"github.com/jmoiron/sqlx"
 )
 
 type Authorization interface {
 	CreateUser(user notes.User) (int, error)
-	GetUser(username, password string) (notes.User, error)
+	GetUser(username string) (notes.User, error)
+	UpdatePasswordHash(userId int, hash string) error
+	CreateRefreshToken(token notes.RefreshToken) error
+	RotateRefreshToken(tokenHash string, next notes.RefreshToken) (notes.RefreshToken, error)
+	RevokeRefreshTokenFamily(userId int, tokenHash string) error
+	RevokeToken(jti string, expiresAt time.Time) error
+	IsTokenRevoked(jti string) (bool, error)
+	CreatePersonalToken(token notes.PersonalAccessToken, tokenHash string) (int, error)
+	GetPersonalTokens(userId int) ([]notes.PersonalAccessToken, error)
+	RevokePersonalToken(userId, tokenId int) error
+	UsePersonalToken(tokenHash string) (notes.PersonalAccessToken, error)
 }
 
 type NotesList interface {
 	Create(userId int, list notes.NotesList) (int, error)
-	GetAll(userId int) ([]notes.NotesList, error)
+	GetAll(userId int, q notes.ListsQuery) ([]notes.NotesList, string, error)
 	GetById(userId, listId int) (notes.NotesList, error)
-	Delete(userId, listId int) error
-	Update(userId, listId int, inp notes.UpdateListInput) error
+	Delete(userId, listId, version int) error
+	Update(userId, listId int, inp notes.UpdateListInput, version int) (int, error)
+	GetRole(userId, listId int) (string, error)
+	GetMembers(listId int) ([]notes.ListMember, error)
+	Share(userId, listId int, username, role string) error
+	Unshare(userId, listId int, username string) error
+	Restore(userId, listId int) error
+	Reorder(userId, listId int, inp notes.ReorderInput) error
 }
 
+// NextOccurrence returns the item following a recurring item in its series,
+// or false once the series has ended.
+type NextOccurrence func(item notes.NotesItem) (notes.NotesItem, bool)
+
 type NotesItem interface {
-	Create(userId int, item notes.NotesItem) (int, error)
-	GetAll(userId, listId int) ([]notes.NotesItem, error)
+	Create(userId, listId int, item notes.NotesItem) (int, error)
+	GetAll(userId, listId int, q notes.ItemsQuery) ([]notes.NotesItem, string, error)
 	GetById(userId, itemId int) (notes.NotesItem, error)
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, inp notes.UpdateItemInput) error
+	GetDue(userId int, from, to *time.Time) ([]notes.ListedItem, error)
+	Delete(userId, itemId, version int) error
+	Update(userId, itemId int, inp notes.UpdateItemInput, version int, next NextOccurrence) (int, error)
+	GetRole(userId, itemId int) (string, error)
+	GetRevisions(itemId int) ([]notes.ItemRevision, error)
+	GetRevision(itemId, version int) (notes.ItemRevision, error)
+	Restore(userId, itemId int) error
+	Move(userId, targetListId int, itemIds []int) error
+	Copy(userId, targetListId int, itemIds []int) ([]int, error)
+	Reorder(userId, itemId int, inp notes.ReorderInput) error
+}
+
+type Invitation interface {
+	Create(inviterId int, inv notes.Invitation) (int, error)
+	GetPending(userId int) ([]notes.Invitation, error)
+	GetByList(listId int) ([]notes.Invitation, error)
+	Accept(userId, invitationId int) (int, error)
+	AcceptLink(userId, invitationId int) (int, error)
+	Decline(userId, invitationId int) error
+	Revoke(listId, invitationId int) error
+	ExpirePending() (int64, error)
+}
+
+type Search interface {
+	Search(userId int, query string, limit int) ([]notes.SearchResult, error)
+}
+
+type Trash interface {
+	GetAll(userId int) ([]notes.TrashEntry, error)
+	Purge(before time.Time) (int64, error)
+}
+
+type Tag interface {
+	Create(userId int, tag notes.Tag) (int, error)
+	GetAll(userId int) ([]notes.Tag, error)
+	GetById(userId, tagId int) (notes.Tag, error)
+	Update(userId, tagId int, inp notes.UpdateTagInput) error
+	Delete(userId, tagId int) error
+	Merge(userId, tagId, intoId int) error
+	GetItemTags(userId, itemId int) ([]notes.Tag, error)
+	TagItem(userId, itemId, tagId int) error
+	UntagItem(userId, itemId, tagId int) error
+	GetItems(userId int, q notes.TaggedItemsQuery) ([]notes.ListedItem, string, error)
+}
+
+type Checklist interface {
+	Create(itemId int, entry notes.ChecklistEntry) (int, error)
+	GetAll(itemId int) ([]notes.ChecklistEntry, error)
+	Update(itemId, entryId int, inp notes.UpdateChecklistEntryInput) error
+	Delete(itemId, entryId int) error
+	Reorder(itemId, entryId int, inp notes.ReorderInput) error
+}
+
+type Link interface {
+	GetLinks(userId, itemId int) ([]notes.ItemLink, error)
+	GetBacklinks(userId, itemId int) ([]notes.ListedItem, error)
+	GetBroken(userId int) ([]notes.BrokenLink, error)
+}
+
+type Folder interface {
+	Create(userId int, folder notes.Folder) (int, error)
+	Update(userId, folderId int, inp notes.UpdateFolderInput) error
+	Delete(userId, folderId int) error
+	Move(userId, folderId int, parentId *int) error
+	PlaceList(userId, listId int, folderId *int) error
+	GetTree(userId int) ([]notes.Folder, []notes.FolderedList, error)
+}
+
+type Reminder interface {
+	Enqueue(channels []string, limit int) (int64, error)
+	Claim(limit int, lease time.Duration) ([]notes.Reminder, error)
+	MarkSent(deliveryId int) error
+	MarkFailed(deliveryId int, reason string) error
+	Retry(deliveryId int, reason string, at time.Time) error
+	GetDeliveries(userId, itemId int) ([]notes.ReminderDelivery, error)
+}
+
+type Inbox interface {
+	Create(deliveryId int, msg notes.InboxMessage) error
+	GetAll(userId int) ([]notes.InboxMessage, error)
+	MarkRead(userId, messageId int) error
 }
 
 type Repository struct {
 	Authorization
 	NotesList
 	NotesItem
+	Invitation
+	Search
+	Trash
+	Tag
+	Checklist
+	Link
+	Folder
+	Reminder
+	Inbox
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
@@ -37,5 +147,14 @@ func NewRepository(db *sqlx.DB) *Repository {
 		Authorization: NewAuthPostgres(db),
 		NotesList:     NewNotesListPostgres(db),
 		NotesItem:     NewNotesItemPostgres(db),
+		Invitation:    NewInvitationPostgres(db),
+		Search:        NewSearchPostgres(db),
+		Trash:         NewTrashPostgres(db),
+		Tag:           NewTagPostgres(db),
+		Checklist:     NewChecklistPostgres(db),
+		Link:          NewLinkPostgres(db),
+		Folder:        NewFolderPostgres(db),
+		Reminder:      NewReminderPostgres(db),
+		Inbox:         NewInboxPostgres(db),
 	}
 }
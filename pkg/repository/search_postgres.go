@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	searchConfig = "english"
+
+	// Matches are delimited by control characters rather than by <mark>
+	// tags, so that the snippet can be escaped before the tags are added.
+	// The characters are removed from descriptions before they are
+	// highlighted so that text can't forge them.
+	matchStart = "\x02"
+	matchStop  = "\x03"
+
+	// headlineOptions delimits matches and keeps snippets short.
+	headlineOptions = "StartSel=" + matchStart + ", StopSel=" + matchStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+var markReplacer = strings.NewReplacer(matchStart, "<mark>", matchStop, "</mark>")
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// Search matches query against the titles and descriptions of the lists
+// the user is a member of and of the items in those lists, best matches
+// first. Trashed lists and items are left out. Matching uses the generated
+// search_vector columns, in which titles weigh more than descriptions.
+// Snippets are escaped, so that the only markup in them is <mark>.
+func (r *SearchPostgres) Search(userId int, query string, limit int) ([]notes.SearchResult, error) {
+	var results []notes.SearchResult
+
+	searchQuery := fmt.Sprintf(
+		`WITH q AS (SELECT websearch_to_tsquery('%[5]s', $2) AS query)
+		SELECT '%[6]s' AS type, tl.id, tl.id AS list_id, tl.title,
+			ts_headline('%[5]s', translate(coalesce(tl.description, ''), '%[9]s', ''), q.query, '%[8]s') AS snippet,
+			ts_rank(tl.search_vector, q.query) AS rank
+		FROM q, %[1]s tl INNER JOIN %[2]s ul on ul.list_id = tl.id
+		WHERE ul.user_id = $1 AND tl.deleted_at IS NULL AND tl.search_vector @@ q.query
+		UNION ALL
+		SELECT '%[7]s' AS type, ti.id, li.list_id, ti.title,
+			ts_headline('%[5]s', translate(ti.description, '%[9]s', ''), q.query, '%[8]s') AS snippet,
+			ts_rank(ti.search_vector, q.query) AS rank
+		FROM q, %[3]s ti INNER JOIN %[4]s li on li.item_id = ti.id INNER JOIN %[2]s ul on ul.list_id = li.list_id INNER JOIN %[1]s tl on tl.id = li.list_id
+		WHERE ul.user_id = $1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL AND ti.search_vector @@ q.query
+		ORDER BY rank DESC, type, id
+		LIMIT $3`,
+		notesListsTable,
+		usersListsTable,
+		notesItemsTable,
+		listsItemsTable,
+		searchConfig,
+		notes.SearchResultList,
+		notes.SearchResultItem,
+		headlineOptions,
+		matchStart+matchStop,
+	)
+	if err := r.db.Select(&results, searchQuery, userId, query, limit); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = markReplacer.Replace(html.EscapeString(results[i].Snippet))
+	}
+
+	return results, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPostgres_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewSearchPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []notes.SearchResult
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"type", "id", "list_id", "title", "snippet", "rank"}).
+					AddRow("item", 4, 1, "groceries", "buy \x02milk\x03", 0.6).
+					AddRow("list", 1, 1, "home", "", 0.1)
+
+				mock.ExpectQuery("WITH q AS \\(SELECT websearch_to_tsquery(.+) FROM q, notes_lists tl INNER JOIN users_lists ul (.+) UNION ALL (.+) FROM q, notes_items ti INNER JOIN lists_items li (.+) ORDER BY rank DESC").
+					WithArgs(1, "milk", 20).
+					WillReturnRows(rows)
+			},
+			want: []notes.SearchResult{
+				{Type: "item", Id: 4, ListId: 1, Title: "groceries", Snippet: "buy <mark>milk</mark>", Rank: 0.6},
+				{Type: "list", Id: 1, ListId: 1, Title: "home", Snippet: "", Rank: 0.1},
+			},
+		},
+		{
+			name: "Markup Escaped",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"type", "id", "list_id", "title", "snippet", "rank"}).
+					AddRow("item", 4, 1, "<b>groceries</b>", "<img src=x onerror=alert(1)> \x02milk\x03 & </mark>", 0.6)
+
+				mock.ExpectQuery("translate\\(ti.description, '\x02\x03', ''\\)").WithArgs(1, "milk", 20).WillReturnRows(rows)
+			},
+			want: []notes.SearchResult{
+				{Type: "item", Id: 4, ListId: 1, Title: "<b>groceries</b>", Snippet: "&lt;img src=x onerror=alert(1)&gt; <mark>milk</mark> &amp; &lt;/mark&gt;", Rank: 0.6},
+			},
+		},
+		{
+			name: "No Results",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"type", "id", "list_id", "title", "snippet", "rank"})
+
+				mock.ExpectQuery("WITH q AS").WithArgs(1, "milk", 20).WillReturnRows(rows)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Search(1, "milk", 20)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TagPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTagPostgres(db *sqlx.DB) *TagPostgres {
+	return &TagPostgres{db: db}
+}
+
+// Create adds a tag for the user. A tag of the same name, ignoring case,
+// is reported as notes.ErrTagExists.
+func (r *TagPostgres) Create(userId int, tag notes.Tag) (int, error) {
+	var id int
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, color) VALUES ($1, $2, $3) RETURNING id", tagsTable)
+	if err := r.db.QueryRow(query, userId, tag.Name, tag.Color).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return -1, notes.ErrTagExists
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (r *TagPostgres) GetAll(userId int) ([]notes.Tag, error) {
+	var tags []notes.Tag
+
+	query := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 ORDER BY lower(name), id", tagsTable)
+	err := r.db.Select(&tags, query, userId)
+
+	return tags, err
+}
+
+func (r *TagPostgres) GetById(userId, tagId int) (notes.Tag, error) {
+	var tag notes.Tag
+
+	query := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 AND id = $2", tagsTable)
+	err := r.db.Get(&tag, query, userId, tagId)
+
+	return tag, err
+}
+
+// Update renames or recolors the tag. Renaming it to the name of another
+// of the user's tags is reported as notes.ErrTagExists; Merge is the way
+// to combine the two.
+func (r *TagPostgres) Update(userId, tagId int, inp notes.UpdateTagInput) error {
+	qValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if inp.Name != nil {
+		qValues = append(qValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *inp.Name)
+		argId++
+	}
+
+	if inp.Color != nil {
+		qValues = append(qValues, fmt.Sprintf("color=$%d", argId))
+		args = append(args, *inp.Color)
+		argId++
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE id = $%d AND user_id = $%d",
+		tagsTable,
+		strings.Join(qValues, ", "),
+		argId,
+		argId+1,
+	)
+	args = append(args, tagId, userId)
+
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return notes.ErrTagExists
+		}
+
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// Delete removes the tag, untagging every item that carried it.
+func (r *TagPostgres) Delete(userId, tagId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", tagsTable)
+
+	res, err := r.db.Exec(query, tagId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// Merge moves the items of the tag over to the tag intoId and deletes it,
+// in one transaction. Both tags must be the user's, otherwise
+// sql.ErrNoRows is returned.
+func (r *TagPostgres) Merge(userId, tagId, intoId int) error {
+	var owned int
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	ownedQuery := fmt.Sprintf("SELECT count(*) FROM %s WHERE user_id = $1 AND id IN ($2, $3)", tagsTable)
+	if err := tx.QueryRow(ownedQuery, userId, tagId, intoId).Scan(&owned); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if owned != 2 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	retagQuery := fmt.Sprintf(
+		"INSERT INTO %s (item_id, tag_id) SELECT item_id, $2 FROM %s WHERE tag_id = $1 ON CONFLICT DO NOTHING",
+		itemTagsTable,
+		itemTagsTable,
+	)
+	if _, err := tx.Exec(retagQuery, tagId, intoId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tagsTable)
+	if _, err := tx.Exec(deleteQuery, tagId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetItemTags returns the tags the user put on the item.
+func (r *TagPostgres) GetItemTags(userId, itemId int) ([]notes.Tag, error) {
+	var tags []notes.Tag
+
+	query := fmt.Sprintf(
+		`SELECT tg.id, tg.name, tg.color FROM %s tg INNER JOIN %s it on it.tag_id = tg.id
+		WHERE tg.user_id = $1 AND it.item_id = $2 ORDER BY lower(tg.name), tg.id`,
+		tagsTable,
+		itemTagsTable,
+	)
+	err := r.db.Select(&tags, query, userId, itemId)
+
+	return tags, err
+}
+
+// TagItem puts the user's tag on the item. Tagging an item twice is not an
+// error.
+func (r *TagPostgres) TagItem(userId, itemId, tagId int) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (item_id, tag_id) SELECT $2, id FROM %s WHERE id = $3 AND user_id = $1 ON CONFLICT DO NOTHING",
+		itemTagsTable,
+		tagsTable,
+	)
+	_, err := r.db.Exec(query, userId, itemId, tagId)
+
+	return err
+}
+
+// UntagItem takes the user's tag off the item; sql.ErrNoRows is returned
+// when the item didn't carry it.
+func (r *TagPostgres) UntagItem(userId, itemId, tagId int) error {
+	query := fmt.Sprintf(
+		"DELETE FROM %s it USING %s tg WHERE tg.id = it.tag_id AND tg.user_id = $1 AND it.item_id = $2 AND it.tag_id = $3",
+		itemTagsTable,
+		tagsTable,
+	)
+
+	res, err := r.db.Exec(query, userId, itemId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res)
+}
+
+// GetItems pages through the items of every list the user can read that
+// carry all, or with notes.TagMatchAny any, of the user's tags in q.
+// Unknown tags and tags of other users match nothing.
+func (r *TagPostgres) GetItems(userId int, q notes.TaggedItemsQuery) ([]notes.ListedItem, string, error) {
+	var items []notes.ListedItem
+
+	p, err := newPage(q.PageQuery)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tagIds := make([]int, 0, len(q.TagIds))
+	seen := make(map[int]bool, len(q.TagIds))
+	for _, id := range q.TagIds {
+		if !seen[id] {
+			tagIds = append(tagIds, id)
+			seen[id] = true
+		}
+	}
+
+	// An item matches when it carries at least this many of the tags.
+	required := len(tagIds)
+	if q.Match == notes.TagMatchAny {
+		required = 1
+	}
+
+	where, tail, pageArgs, err := p.clause("ti", 4)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %[9]s, li.list_id
+		FROM %[1]s ti INNER JOIN %[2]s li on li.item_id = ti.id INNER JOIN %[3]s ul on ul.list_id = li.list_id INNER JOIN %[4]s tl on tl.id = li.list_id
+		WHERE ul.user_id = $1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL
+		AND (SELECT count(*) FROM %[5]s it INNER JOIN %[6]s tg on tg.id = it.tag_id WHERE it.item_id = ti.id AND tg.user_id = $1 AND it.tag_id = ANY($2)) >= $3%[7]s%[8]s`,
+		notesItemsTable,
+		listsItemsTable,
+		usersListsTable,
+		notesListsTable,
+		itemTagsTable,
+		tagsTable,
+		where,
+		tail,
+		itemColumns,
+	)
+	args := append([]interface{}{userId, pq.Array(tagIds), required}, pageArgs...)
+
+	if err := r.db.Select(&items, query, args...); err != nil {
+		return nil, "", err
+	}
+
+	fetched := len(items)
+	if fetched > p.Limit {
+		items = items[:p.Limit]
+	}
+
+	var next string
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		next = p.next(fetched, last.Id, last.Title, last.Position, last.CreatedAt, last.UpdatedAt)
+	}
+
+	return items, next, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTagPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO tags \\(user_id, name, color\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id").
+					WithArgs(1, "work", "#ff0000").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			},
+			want: 3,
+		},
+		{
+			name: "Duplicate Name",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO tags").
+					WithArgs(1, "work", "#ff0000").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			want:    -1,
+			wantErr: notes.ErrTagExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Create(1, notes.Tag{Name: "work", Color: "#ff0000"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTagPostgres(sqlxDb)
+
+	name, color := "home", "#00ff00"
+
+	tests := []struct {
+		name    string
+		input   notes.UpdateTagInput
+		mock    func()
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: notes.UpdateTagInput{Name: &name, Color: &color},
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET name=\\$1, color=\\$2 WHERE id = \\$3 AND user_id = \\$4").
+					WithArgs("home", "#00ff00", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:  "Color Only",
+			input: notes.UpdateTagInput{Color: &color},
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET color=\\$1 WHERE id = \\$2 AND user_id = \\$3").
+					WithArgs("#00ff00", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:  "Name Taken",
+			input: notes.UpdateTagInput{Name: &name},
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET name=\\$1").
+					WithArgs("home", 2, 1).
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr: notes.ErrTagExists,
+		},
+		{
+			name:  "Not Found",
+			input: notes.UpdateTagInput{Name: &name},
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET name=\\$1").
+					WithArgs("home", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Update(1, 2, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagPostgres_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTagPostgres(sqlxDb)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM tags WHERE user_id = \\$1 AND id IN \\(\\$2, \\$3\\)").
+					WithArgs(1, 2, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec("INSERT INTO item_tags \\(item_id, tag_id\\) SELECT item_id, \\$2 FROM item_tags WHERE tag_id = \\$1 ON CONFLICT DO NOTHING").
+					WithArgs(2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec("DELETE FROM tags WHERE id = \\$1").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Foreign Tag",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM tags").
+					WithArgs(1, 2, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.Merge(1, 2, 3)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagPostgres_TagItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTagPostgres(sqlxDb)
+
+	mock.ExpectExec("INSERT INTO item_tags \\(item_id, tag_id\\) SELECT \\$2, id FROM tags WHERE id = \\$3 AND user_id = \\$1 ON CONFLICT DO NOTHING").
+		WithArgs(1, 4, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.TagItem(1, 4, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectExec("DELETE FROM item_tags it USING tags tg WHERE (.+) AND tg.user_id = \\$1 AND it.item_id = \\$2 AND it.tag_id = \\$3").
+		WithArgs(1, 4, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, r.UntagItem(1, 4, 2), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagPostgres_GetItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTagPostgres(sqlxDb)
+
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "title", "description", "archived", "position", "created_at", "updated_at", "version", "list_id"}
+
+	tests := []struct {
+		name  string
+		query notes.TaggedItemsQuery
+		mock  func()
+		want  []notes.ListedItem
+	}{
+		{
+			name:  "All",
+			query: notes.TaggedItemsQuery{TagIds: []int{2, 3, 2}, PageQuery: notes.PageQuery{Sort: notes.SortUpdated}},
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+), li.list_id FROM notes_items ti (.+) WHERE ul.user_id = \\$1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL AND \\(SELECT count\\(\\*\\) FROM item_tags it INNER JOIN tags tg (.+) AND it.tag_id = ANY\\(\\$2\\)\\) >= \\$3 ORDER BY ti.updated_at ASC, ti.id ASC LIMIT \\$4").
+					WithArgs(1, "{2,3}", 2, notes.DefaultPageLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "title", "description", false, "i", updated, updated, 1, 7))
+			},
+			want: []notes.ListedItem{
+				{
+					NotesItem: notes.NotesItem{Id: 4, Title: "title", Description: "description", Position: "i", CreatedAt: updated, UpdatedAt: updated, Version: 1},
+					ListId:    7,
+				},
+			},
+		},
+		{
+			name:  "Any",
+			query: notes.TaggedItemsQuery{TagIds: []int{2, 3}, Match: notes.TagMatchAny, PageQuery: notes.PageQuery{Sort: notes.SortUpdated}},
+			mock: func() {
+				mock.ExpectQuery(">= \\$3 ORDER BY").
+					WithArgs(1, "{2,3}", 1, notes.DefaultPageLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, next, err := r.GetItems(1, tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, next)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+)
+
+type TrashPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTrashPostgres(db *sqlx.DB) *TrashPostgres {
+	return &TrashPostgres{db: db}
+}
+
+// GetAll returns what the user could restore, most recently deleted first:
+// the trashed lists they own and the trashed items of lists they can edit.
+// Items of trashed lists are left out, they are restored with their list.
+func (r *TrashPostgres) GetAll(userId int) ([]notes.TrashEntry, error) {
+	var entries []notes.TrashEntry
+
+	query := fmt.Sprintf(
+		`SELECT '%[6]s' AS type, tl.id, tl.id AS list_id, tl.title, tl.deleted_at
+		FROM %[1]s tl INNER JOIN %[2]s ul on ul.list_id = tl.id
+		WHERE ul.user_id = $1 AND ul.role IN (%[4]s) AND tl.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT '%[7]s' AS type, ti.id, li.list_id, ti.title, ti.deleted_at
+		FROM %[3]s ti INNER JOIN %[8]s li on li.item_id = ti.id INNER JOIN %[1]s tl on tl.id = li.list_id INNER JOIN %[2]s ul on ul.list_id = li.list_id
+		WHERE ul.user_id = $1 AND ul.role IN (%[5]s) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL
+		ORDER BY deleted_at DESC, type, id`,
+		notesListsTable,
+		usersListsTable,
+		notesItemsTable,
+		ownerRoles,
+		writeRoles,
+		notes.TrashList,
+		notes.TrashItem,
+		listsItemsTable,
+	)
+	err := r.db.Select(&entries, query, userId)
+
+	return entries, err
+}
+
+// Purge permanently deletes the lists and items trashed before the given
+// time, together with the items of the purged lists, and returns how many
+// rows were removed.
+func (r *TrashPostgres) Purge(before time.Time) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	// Items go first: purging a list cascades through lists_items, after
+	// which its items could no longer be found.
+	itemsQuery := fmt.Sprintf(
+		`DELETE FROM %s ti WHERE ti.deleted_at < $1
+		OR EXISTS (SELECT 1 FROM %s li INNER JOIN %s tl on tl.id = li.list_id WHERE li.item_id = ti.id AND tl.deleted_at < $1)`,
+		notesItemsTable,
+		listsItemsTable,
+		notesListsTable,
+	)
+	res, err := tx.Exec(itemsQuery, before)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	items, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	listsQuery := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", notesListsTable)
+	res, err = tx.Exec(listsQuery, before)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	lists, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return items + lists, tx.Commit()
+}
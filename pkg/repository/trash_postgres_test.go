@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Liopun/notes-app"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTrashPostgres(sqlxDb)
+
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"type", "id", "list_id", "title", "deleted_at"}).
+		AddRow(notes.TrashList, 2, 2, "list", deletedAt).
+		AddRow(notes.TrashItem, 5, 1, "item", deletedAt)
+	mock.ExpectQuery("SELECT 'list' AS type, (.+) FROM notes_lists tl (.+) AND ul.role IN \\('owner'\\) AND tl.deleted_at IS NOT NULL UNION ALL SELECT 'item' AS type, (.+) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL ORDER BY deleted_at DESC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	got, err := r.GetAll(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []notes.TrashEntry{
+		{Type: notes.TrashList, Id: 2, ListId: 2, Title: "list", DeletedAt: deletedAt},
+		{Type: notes.TrashItem, Id: 5, ListId: 1, Title: "item", DeletedAt: deletedAt},
+	}, got)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrashPostgres_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error occured '%s' was not expected for stub database connection", err)
+	}
+
+	sqlxDb := sqlx.NewDb(db, "sqlmock")
+
+	defer sqlxDb.Close()
+
+	r := NewTrashPostgres(sqlxDb)
+
+	before := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM notes_items ti WHERE ti.deleted_at < \\$1 OR EXISTS (.+) tl.deleted_at < \\$1").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("DELETE FROM notes_lists WHERE deleted_at < \\$1").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 4,
+		},
+		{
+			name: "Lists Failure",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM notes_items").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("DELETE FROM notes_lists").
+					WithArgs(before).
+					WillReturnError(errors.New("delete error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.Purge(before)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package service
 
 import (
-	"crypto/sha1"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Liopun/notes-app"
 	"github.com/Liopun/notes-app/pkg/repository"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
 )
 
 type tokenClaims struct {
@@ -17,68 +22,265 @@ type tokenClaims struct {
 }
 
 type AuthService struct {
-	repo         repository.Authorization
-	passwordSalt string
-	signingKey   string
-	tokenTTL     time.Duration
+	repo            repository.Authorization
+	passwordSalt    string
+	keys            *KeySet
+	tokenTTL        time.Duration
+	refreshTokenTTL time.Duration
 }
 
-func NewAuthService(repo repository.Authorization, passwordSalt, signingKey string, tokenTTL time.Duration) *AuthService {
+func NewAuthService(repo repository.Authorization, passwordSalt string, keys *KeySet, tokenTTL, refreshTokenTTL time.Duration) *AuthService {
 	return &AuthService{
-		repo:         repo,
-		passwordSalt: passwordSalt,
-		signingKey:   signingKey,
-		tokenTTL:     tokenTTL,
+		repo:            repo,
+		passwordSalt:    passwordSalt,
+		keys:            keys,
+		tokenTTL:        tokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
 	}
 }
 
 func (s *AuthService) CreateUser(user notes.User) (int, error) {
-	user.Password = generateHashedPasswword(user.Password, s.passwordSalt)
+	hash, err := hashPassword(user.Password)
+	if err != nil {
+		return -1, err
+	}
+	user.Password = hash
 
 	return s.repo.CreateUser(user)
 }
 
-func (s *AuthService) GenerateToken(username, password string) (string, error) {
-	user, err := s.repo.GetUser(username, generateHashedPasswword(password, s.passwordSalt))
+// GenerateToken signs the user in, starting a new refresh token family.
+func (s *AuthService) GenerateToken(username, password string) (notes.Tokens, error) {
+	user, err := s.authenticate(username, password)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	familyId, err := generateRandomToken(16)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	refreshToken, err := generateRandomToken(32)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	err = s.repo.CreateRefreshToken(notes.RefreshToken{
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	accessToken, err := s.newAccessToken(user.Id)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	return notes.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Every
+// refresh token can be used once; see repository.RotateRefreshToken.
+func (s *AuthService) RefreshToken(refreshToken string) (notes.Tokens, error) {
+	next, err := generateRandomToken(32)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	rotated, err := s.repo.RotateRefreshToken(hashToken(refreshToken), notes.RefreshToken{
+		TokenHash: hashToken(next),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	accessToken, err := s.newAccessToken(rotated.UserId)
+	if err != nil {
+		return notes.Tokens{}, err
+	}
+
+	return notes.Tokens{AccessToken: accessToken, RefreshToken: next}, nil
+}
+
+// Logout revokes the access token and, when given, the refresh token family
+// it was issued with.
+func (s *AuthService) Logout(accessToken, refreshToken string) error {
+	claims, err := s.parseClaims(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		if err := s.repo.RevokeRefreshTokenFamily(claims.UserId, hashToken(refreshToken)); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RevokeToken(claims.ID, claims.ExpiresAt.Time)
+}
+
+func (s *AuthService) ParseToken(accessToken string) (int, error) {
+	claims, err := s.parseClaims(accessToken)
+	if err != nil {
+		return -1, err
+	}
+
+	revoked, err := s.repo.IsTokenRevoked(claims.ID)
+	if err != nil {
+		return -1, err
+	}
+
+	if revoked {
+		return -1, notes.ErrInvalidToken
+	}
+
+	return claims.UserId, nil
+}
+
+func (s *AuthService) JWKS() notes.JSONWebKeySet {
+	return s.keys.JWKS()
+}
+
+func (s *AuthService) CreatePersonalToken(userId int, inp notes.CreateTokenInput) (notes.PersonalAccessToken, error) {
+	if err := inp.Validate(); err != nil {
+		return notes.PersonalAccessToken{}, err
+	}
+
+	secret, err := generateRandomToken(32)
+	if err != nil {
+		return notes.PersonalAccessToken{}, err
+	}
+
+	token := notes.PersonalAccessToken{
+		UserId:    userId,
+		Name:      inp.Name,
+		Scopes:    inp.Scopes,
+		Token:     notes.PersonalTokenPrefix + secret,
+		CreatedAt: time.Now(),
+		ExpiresAt: inp.ExpiresAt,
+	}
+
+	token.Id, err = s.repo.CreatePersonalToken(token, hashToken(token.Token))
+	if err != nil {
+		return notes.PersonalAccessToken{}, err
+	}
+
+	return token, nil
+}
+
+func (s *AuthService) GetPersonalTokens(userId int) ([]notes.PersonalAccessToken, error) {
+	return s.repo.GetPersonalTokens(userId)
+}
+
+func (s *AuthService) RevokePersonalToken(userId, tokenId int) error {
+	return s.repo.RevokePersonalToken(userId, tokenId)
+}
+
+// ParsePersonalToken resolves a personal access token to its owner and the
+// scopes it was granted. Revoked, expired and unknown tokens are all
+// reported as notes.ErrInvalidToken.
+func (s *AuthService) ParsePersonalToken(token string) (int, notes.Scopes, error) {
+	if !strings.HasPrefix(token, notes.PersonalTokenPrefix) {
+		return -1, nil, notes.ErrInvalidToken
+	}
+
+	pat, err := s.repo.UsePersonalToken(hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, nil, notes.ErrInvalidToken
+		}
+		return -1, nil, err
+	}
+
+	return pat.UserId, pat.Scopes, nil
+}
+
+func (s *AuthService) newAccessToken(userId int) (string, error) {
+	jti, err := generateRandomToken(16)
 	if err != nil {
 		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+	return s.keys.Sign(&tokenClaims{
 		jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.tokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
-		user.Id,
+		userId,
 	})
+}
+
+func (s *AuthService) parseClaims(accessToken string) (*tokenClaims, error) {
+	claims := &tokenClaims{}
+	if err := s.keys.Parse(accessToken, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, notes.ErrInvalidToken
+	}
 
-	return token.SignedString([]byte(s.signingKey))
+	return claims, nil
 }
 
-func (s *AuthService) ParseToken(accessToken string) (int, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
+// authenticate looks the user up by username and verifies the password
+// against the stored hash. Unknown users take as long to reject as wrong
+// passwords. Hashes using an outdated scheme are replaced on success; a
+// failed upgrade is logged but does not fail the sign-in.
+func (s *AuthService) authenticate(username, password string) (notes.User, error) {
+	user, err := s.repo.GetUser(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		verifyDummyPassword(password)
+		return user, notes.ErrInvalidCredentials
+	}
 
-		return []byte(s.signingKey), nil
-	})
 	if err != nil {
-		return -1, err
+		return user, err
+	}
+
+	ok, needsRehash, err := verifyPassword(password, user.Password, s.passwordSalt)
+	if err != nil {
+		return user, err
 	}
 
-	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return -1, errors.New("token claims invalid type")
+		return user, notes.ErrInvalidCredentials
 	}
 
-	return claims.UserId, nil
+	if needsRehash {
+		hash, err := hashPassword(password)
+		if err == nil {
+			err = s.repo.UpdatePasswordHash(user.Id, hash)
+		}
+
+		if err != nil {
+			logrus.Errorf("failed to rehash password of user %d: %s", user.Id, err.Error())
+		}
+	}
+
+	return user, nil
+}
+
+// generateRandomToken returns n random bytes encoded as URL-safe base64.
+func generateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-func generateHashedPasswword(password, salt string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAuthRepo keeps users in memory. Methods a test does not exercise fall
+// through to the embedded nil interface and panic.
+type fakeAuthRepo struct {
+	repository.Authorization
+	users         map[string]notes.User
+	refreshTokens map[string]notes.RefreshToken
+	revoked       map[string]bool
+	personal      map[string]notes.PersonalAccessToken
+}
+
+func newFakeAuthRepo() *fakeAuthRepo {
+	return &fakeAuthRepo{
+		users:         make(map[string]notes.User),
+		refreshTokens: make(map[string]notes.RefreshToken),
+		revoked:       make(map[string]bool),
+		personal:      make(map[string]notes.PersonalAccessToken),
+	}
+}
+
+func (r *fakeAuthRepo) CreateUser(user notes.User) (int, error) {
+	user.Id = len(r.users) + 1
+	r.users[user.Username] = user
+
+	return user.Id, nil
+}
+
+func (r *fakeAuthRepo) GetUser(username string) (notes.User, error) {
+	user, ok := r.users[username]
+	if !ok {
+		return user, sql.ErrNoRows
+	}
+
+	return user, nil
+}
+
+func (r *fakeAuthRepo) UpdatePasswordHash(userId int, hash string) error {
+	for name, user := range r.users {
+		if user.Id == userId {
+			user.Password = hash
+			r.users[name] = user
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeAuthRepo) CreateRefreshToken(token notes.RefreshToken) error {
+	r.refreshTokens[token.TokenHash] = token
+
+	return nil
+}
+
+func (r *fakeAuthRepo) RotateRefreshToken(tokenHash string, next notes.RefreshToken) (notes.RefreshToken, error) {
+	current, ok := r.refreshTokens[tokenHash]
+	if !ok || current.RevokedAt != nil {
+		return next, notes.ErrInvalidToken
+	}
+
+	if current.UsedAt != nil {
+		r.revokeFamily(current.FamilyId)
+		return next, notes.ErrTokenReused
+	}
+
+	now := time.Now()
+	current.UsedAt = &now
+	r.refreshTokens[tokenHash] = current
+
+	next.UserId = current.UserId
+	next.FamilyId = current.FamilyId
+	r.refreshTokens[next.TokenHash] = next
+
+	return next, nil
+}
+
+func (r *fakeAuthRepo) RevokeRefreshTokenFamily(userId int, tokenHash string) error {
+	if token, ok := r.refreshTokens[tokenHash]; ok && token.UserId == userId {
+		r.revokeFamily(token.FamilyId)
+	}
+
+	return nil
+}
+
+func (r *fakeAuthRepo) revokeFamily(familyId string) {
+	now := time.Now()
+	for hash, token := range r.refreshTokens {
+		if token.FamilyId == familyId && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r.refreshTokens[hash] = token
+		}
+	}
+}
+
+func (r *fakeAuthRepo) RevokeToken(jti string, expiresAt time.Time) error {
+	r.revoked[jti] = true
+
+	return nil
+}
+
+func (r *fakeAuthRepo) IsTokenRevoked(jti string) (bool, error) {
+	return r.revoked[jti], nil
+}
+
+func (r *fakeAuthRepo) CreatePersonalToken(token notes.PersonalAccessToken, tokenHash string) (int, error) {
+	token.Id = len(r.personal) + 1
+	r.personal[tokenHash] = token
+
+	return token.Id, nil
+}
+
+func (r *fakeAuthRepo) UsePersonalToken(tokenHash string) (notes.PersonalAccessToken, error) {
+	token, ok := r.personal[tokenHash]
+	if !ok {
+		return token, sql.ErrNoRows
+	}
+
+	return token, nil
+}
+
+func newHMACKeySet(secret string) *KeySet {
+	keys, _ := NewKeySet("hs256", NewHMACKey("hs256", secret))
+
+	return keys
+}
+
+func TestAuthService_CreateUser(t *testing.T) {
+	repo := newFakeAuthRepo()
+	s := NewAuthService(repo, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.CreateUser(notes.User{Name: "Test", Username: "test", Password: "qwerty"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "qwerty", repo.users["test"].Password)
+	assert.Contains(t, repo.users["test"].Password, "$argon2id$")
+}
+
+func TestAuthService_GenerateToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     string
+		password   string
+		wantErr    bool
+		wantRehash bool
+	}{
+		{
+			name:     "OK",
+			password: "qwerty",
+		},
+		{
+			name:       "Legacy Hash Upgraded",
+			stored:     generateLegacyHash("qwerty", "salt"),
+			password:   "qwerty",
+			wantRehash: true,
+		},
+		{
+			name:     "Legacy Hash Wrong Password",
+			stored:   generateLegacyHash("qwerty", "salt"),
+			password: "wrong",
+			wantErr:  true,
+		},
+		{
+			name:     "Wrong Password",
+			password: "wrong",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAuthRepo()
+			s := NewAuthService(repo, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+			_, err := s.CreateUser(notes.User{Name: "Test", Username: "test", Password: "qwerty"})
+			assert.NoError(t, err)
+
+			if tt.stored != "" {
+				repo.UpdatePasswordHash(1, tt.stored)
+			}
+
+			before := repo.users["test"].Password
+
+			tokens, err := s.GenerateToken("test", tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, before, repo.users["test"].Password)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			userId, err := s.ParseToken(tokens.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, userId)
+
+			if tt.wantRehash {
+				assert.Contains(t, repo.users["test"].Password, "$argon2id$")
+			}
+		})
+	}
+}
+
+func TestAuthService_GenerateToken_UnknownUser(t *testing.T) {
+	s := NewAuthService(newFakeAuthRepo(), "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.GenerateToken("nobody", "qwerty")
+	assert.ErrorIs(t, err, notes.ErrInvalidCredentials)
+	assert.True(t, strings.HasPrefix(dummyHash, "$argon2id$"), "the password is checked against a dummy hash")
+}
+
+type failingAuthRepo struct {
+	*fakeAuthRepo
+	err error
+}
+
+func (r failingAuthRepo) GetUser(username string) (notes.User, error) {
+	return notes.User{}, r.err
+}
+
+func TestAuthService_GenerateToken_RepositoryFailure(t *testing.T) {
+	outage := errors.New("connection refused")
+	s := NewAuthService(failingAuthRepo{newFakeAuthRepo(), outage}, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.GenerateToken("test", "qwerty")
+	assert.ErrorIs(t, err, outage)
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	repo := newFakeAuthRepo()
+	s := NewAuthService(repo, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.CreateUser(notes.User{Name: "Test", Username: "test", Password: "qwerty"})
+	assert.NoError(t, err)
+
+	first, err := s.GenerateToken("test", "qwerty")
+	assert.NoError(t, err)
+
+	second, err := s.RefreshToken(first.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	userId, err := s.ParseToken(second.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userId)
+
+	// Replaying the rotated token kills the family, including the token
+	// that was legitimately issued in exchange for it.
+	_, err = s.RefreshToken(first.RefreshToken)
+	assert.ErrorIs(t, err, notes.ErrTokenReused)
+
+	_, err = s.RefreshToken(second.RefreshToken)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+
+	_, err = s.RefreshToken("unknown")
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	repo := newFakeAuthRepo()
+	s := NewAuthService(repo, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.CreateUser(notes.User{Name: "Test", Username: "test", Password: "qwerty"})
+	assert.NoError(t, err)
+
+	tokens, err := s.GenerateToken("test", "qwerty")
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Logout(tokens.AccessToken, tokens.RefreshToken))
+
+	_, err = s.ParseToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+
+	_, err = s.RefreshToken(tokens.RefreshToken)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+}
+
+func TestAuthService_ParseToken_Invalid(t *testing.T) {
+	s := NewAuthService(newFakeAuthRepo(), "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+	other := NewAuthService(newFakeAuthRepo(), "salt", newHMACKeySet("other key"), time.Hour, 24*time.Hour)
+
+	token, err := other.newAccessToken(1)
+	assert.NoError(t, err)
+
+	_, err = s.ParseToken(token)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+}
+
+func TestAuthService_PersonalToken(t *testing.T) {
+	repo := newFakeAuthRepo()
+	s := NewAuthService(repo, "salt", newHMACKeySet("key"), time.Hour, 24*time.Hour)
+
+	_, err := s.CreatePersonalToken(1, notes.CreateTokenInput{Name: "ci", Scopes: notes.Scopes{"admin"}})
+	assert.Error(t, err)
+
+	token, err := s.CreatePersonalToken(1, notes.CreateTokenInput{Name: "ci", Scopes: notes.Scopes{notes.ScopeItemsRead}})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token.Token, notes.PersonalTokenPrefix))
+
+	_, storedPlain := repo.personal[token.Token]
+	assert.False(t, storedPlain, "only the token hash may be stored")
+
+	userId, scopes, err := s.ParsePersonalToken(token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userId)
+	assert.Equal(t, notes.Scopes{notes.ScopeItemsRead}, scopes)
+
+	_, _, err = s.ParsePersonalToken(notes.PersonalTokenPrefix + "unknown")
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+type ChecklistService struct {
+	repo     repository.Checklist
+	itemRepo repository.NotesItem
+}
+
+func NewChecklistService(repo repository.Checklist, itemRepo repository.NotesItem) *ChecklistService {
+	return &ChecklistService{
+		repo:     repo,
+		itemRepo: itemRepo,
+	}
+}
+
+// Create adds an entry to the end of the item's checklist. The checklist
+// is part of the item, so changing it takes an editor.
+func (s *ChecklistService) Create(userId, itemId int, entry notes.ChecklistEntry) (int, error) {
+	if err := entry.Validate(); err != nil {
+		return -1, err
+	}
+
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return -1, err
+	}
+
+	return s.repo.Create(itemId, entry)
+}
+
+func (s *ChecklistService) GetAll(userId, itemId int) ([]notes.ChecklistEntry, error) {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAll(itemId)
+}
+
+func (s *ChecklistService) Update(userId, itemId, entryId int, inp notes.UpdateChecklistEntryInput) error {
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Update(itemId, entryId, inp)
+}
+
+func (s *ChecklistService) Delete(userId, itemId, entryId int) error {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(itemId, entryId)
+}
+
+func (s *ChecklistService) Reorder(userId, itemId, entryId int, inp notes.ReorderInput) error {
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Reorder(itemId, entryId, inp)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChecklistRepo struct {
+	repository.Checklist
+	created []notes.ChecklistEntry
+}
+
+func (r *fakeChecklistRepo) Create(itemId int, entry notes.ChecklistEntry) (int, error) {
+	r.created = append(r.created, entry)
+	return len(r.created), nil
+}
+
+func (r *fakeChecklistRepo) GetAll(itemId int) ([]notes.ChecklistEntry, error) {
+	return r.created, nil
+}
+
+func TestChecklistService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		itemId  int
+		entry   notes.ChecklistEntry
+		wantErr error
+	}{
+		{name: "Editor", itemId: 1, entry: notes.ChecklistEntry{Text: "buy milk"}},
+		{name: "Viewer", itemId: 2, entry: notes.ChecklistEntry{Text: "buy milk"}, wantErr: notes.ErrForbidden},
+		{name: "Unknown Item", itemId: 3, entry: notes.ChecklistEntry{Text: "buy milk"}, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeChecklistRepo{}
+			s := NewChecklistService(repo, &fakeItemRepo{roles: map[int]string{1: notes.RoleEditor, 2: notes.RoleViewer}})
+
+			_, err := s.Create(1, tt.itemId, tt.entry)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []notes.ChecklistEntry{tt.entry}, repo.created)
+			}
+		})
+	}
+}
+
+func TestChecklistService_GetAll(t *testing.T) {
+	s := NewChecklistService(&fakeChecklistRepo{}, &fakeItemRepo{roles: map[int]string{2: notes.RoleViewer}})
+
+	_, err := s.GetAll(1, 2)
+	assert.NoError(t, err, "viewers can read the checklist")
+
+	assert.Error(t, s.Update(1, 2, 7, notes.UpdateChecklistEntryInput{Checked: new(bool)}), "but not check entries")
+}
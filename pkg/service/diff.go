@@ -0,0 +1,107 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Liopun/notes-app"
+)
+
+// maxDiffCells bounds the LCS table, which keeps it to about 1.6MB per
+// request. Texts whose differing lines span more cells than that are
+// reported as replaced wholesale rather than diffed.
+const maxDiffCells = 400_000
+
+// diffLines returns a line-level diff turning a into b, based on the longest
+// common subsequence of their lines.
+func diffLines(a, b string) []notes.DiffLine {
+	x, y := splitLines(a), splitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]notes.DiffLine, 0, len(x)+len(y))
+	for _, line := range x[:prefix] {
+		diff = append(diff, notes.DiffLine{Op: notes.DiffEqual, Text: line})
+	}
+
+	diff = append(diff, diffMiddle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+
+	for _, line := range x[len(x)-suffix:] {
+		diff = append(diff, notes.DiffLine{Op: notes.DiffEqual, Text: line})
+	}
+
+	return diff
+}
+
+func diffMiddle(x, y []string) []notes.DiffLine {
+	var diff []notes.DiffLine
+
+	if len(x)*len(y) > maxDiffCells {
+		for _, line := range x {
+			diff = append(diff, notes.DiffLine{Op: notes.DiffDelete, Text: line})
+		}
+		for _, line := range y {
+			diff = append(diff, notes.DiffLine{Op: notes.DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the LCS of x[i:] and y[j:].
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			diff = append(diff, notes.DiffLine{Op: notes.DiffEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, notes.DiffLine{Op: notes.DiffDelete, Text: x[i]})
+			i++
+		default:
+			diff = append(diff, notes.DiffLine{Op: notes.DiffInsert, Text: y[j]})
+			j++
+		}
+	}
+
+	for ; i < len(x); i++ {
+		diff = append(diff, notes.DiffLine{Op: notes.DiffDelete, Text: x[i]})
+	}
+
+	for ; j < len(y); j++ {
+		diff = append(diff, notes.DiffLine{Op: notes.DiffInsert, Text: y[j]})
+	}
+
+	return diff
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	eq := func(s string) notes.DiffLine { return notes.DiffLine{Op: notes.DiffEqual, Text: s} }
+	ins := func(s string) notes.DiffLine { return notes.DiffLine{Op: notes.DiffInsert, Text: s} }
+	del := func(s string) notes.DiffLine { return notes.DiffLine{Op: notes.DiffDelete, Text: s} }
+
+	tests := []struct {
+		name string
+		a, b string
+		want []notes.DiffLine
+	}{
+		{
+			name: "Equal",
+			a:    "one\ntwo",
+			b:    "one\ntwo\n",
+			want: []notes.DiffLine{eq("one"), eq("two")},
+		},
+		{
+			name: "Both Empty",
+			want: []notes.DiffLine{},
+		},
+		{
+			name: "From Empty",
+			b:    "one\ntwo",
+			want: []notes.DiffLine{ins("one"), ins("two")},
+		},
+		{
+			name: "Changed Line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []notes.DiffLine{eq("one"), del("two"), ins("2"), eq("three")},
+		},
+		{
+			name: "Moved Line",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\na\nd",
+			want: []notes.DiffLine{del("a"), eq("b"), eq("c"), ins("a"), eq("d")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diffLines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestDiffLines_TooLarge(t *testing.T) {
+	a := strings.Repeat("a\n", 700)
+	b := strings.Repeat("b\n", 700)
+
+	diff := diffLines(a, b)
+	assert.Len(t, diff, 1400)
+	assert.Equal(t, notes.DiffDelete, diff[0].Op)
+	assert.Equal(t, notes.DiffInsert, diff[1399].Op)
+
+	// The shared prefix and suffix don't count towards the limit.
+	common := strings.Repeat("c\n", 5000)
+	diff = diffLines(common+"a\n"+common, common+"b\n"+common)
+	assert.Len(t, diff, 10002)
+	assert.Equal(t, notes.DiffLine{Op: notes.DiffDelete, Text: "a"}, diff[5000])
+	assert.Equal(t, notes.DiffLine{Op: notes.DiffInsert, Text: "b"}, diff[5001])
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+type FolderService struct {
+	repo repository.Folder
+}
+
+func NewFolderService(repo repository.Folder) *FolderService {
+	return &FolderService{repo: repo}
+}
+
+func (s *FolderService) Create(userId int, folder notes.Folder) (int, error) {
+	folder.Name = strings.TrimSpace(folder.Name)
+	if err := folder.Validate(); err != nil {
+		return -1, err
+	}
+
+	return s.repo.Create(userId, folder)
+}
+
+func (s *FolderService) Update(userId, folderId int, inp notes.UpdateFolderInput) error {
+	if inp.Name != nil {
+		name := strings.TrimSpace(*inp.Name)
+		inp.Name = &name
+	}
+
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(userId, folderId, inp)
+}
+
+func (s *FolderService) Delete(userId, folderId int) error {
+	return s.repo.Delete(userId, folderId)
+}
+
+func (s *FolderService) Move(userId, folderId int, inp notes.MoveFolderInput) error {
+	return s.repo.Move(userId, folderId, inp.ParentId)
+}
+
+func (s *FolderService) PlaceList(userId, listId int, inp notes.PlaceListInput) error {
+	return s.repo.PlaceList(userId, listId, inp.FolderId)
+}
+
+// GetTree returns the user's folders nested into each other, with every
+// list they are a member of in the folder they keep it in.
+func (s *FolderService) GetTree(userId int) (notes.FolderTree, error) {
+	folders, lists, err := s.repo.GetTree(userId)
+	if err != nil {
+		return notes.FolderTree{}, err
+	}
+
+	return buildFolderTree(folders, lists), nil
+}
+
+// buildFolderTree nests folders under their parents. Lists in a folder
+// that isn't among folders are put at the top level.
+func buildFolderTree(folders []notes.Folder, lists []notes.FolderedList) notes.FolderTree {
+	subfolders := make(map[int][]notes.Folder)
+	known := make(map[int]bool, len(folders))
+	var roots []notes.Folder
+	for _, f := range folders {
+		known[f.Id] = true
+		if f.ParentId == nil {
+			roots = append(roots, f)
+			continue
+		}
+		subfolders[*f.ParentId] = append(subfolders[*f.ParentId], f)
+	}
+
+	tree := notes.FolderTree{
+		Folders: make([]notes.FolderNode, 0, len(roots)),
+		Lists:   make([]notes.NotesList, 0),
+	}
+
+	contents := make(map[int][]notes.NotesList)
+	for _, l := range lists {
+		if l.FolderId == nil || !known[*l.FolderId] {
+			tree.Lists = append(tree.Lists, l.NotesList)
+			continue
+		}
+		contents[*l.FolderId] = append(contents[*l.FolderId], l.NotesList)
+	}
+
+	var node func(f notes.Folder) notes.FolderNode
+	node = func(f notes.Folder) notes.FolderNode {
+		n := notes.FolderNode{
+			Folder:  f,
+			Folders: make([]notes.FolderNode, 0, len(subfolders[f.Id])),
+			Lists:   contents[f.Id],
+		}
+		if n.Lists == nil {
+			n.Lists = make([]notes.NotesList, 0)
+		}
+
+		for _, sub := range subfolders[f.Id] {
+			n.Folders = append(n.Folders, node(sub))
+		}
+
+		return n
+	}
+
+	for _, f := range roots {
+		tree.Folders = append(tree.Folders, node(f))
+	}
+
+	return tree
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFolderRepo struct {
+	repository.Folder
+	folders []notes.Folder
+	lists   []notes.FolderedList
+	created []notes.Folder
+}
+
+func (r *fakeFolderRepo) Create(userId int, folder notes.Folder) (int, error) {
+	r.created = append(r.created, folder)
+	return len(r.created), nil
+}
+
+func (r *fakeFolderRepo) GetTree(userId int) ([]notes.Folder, []notes.FolderedList, error) {
+	return r.folders, r.lists, nil
+}
+
+func TestFolderService_Create(t *testing.T) {
+	repo := &fakeFolderRepo{}
+	s := NewFolderService(repo)
+
+	_, err := s.Create(1, notes.Folder{Name: " Work  "})
+	assert.NoError(t, err)
+	assert.Equal(t, notes.Folder{Name: "Work"}, repo.created[0])
+
+	_, err = s.Create(1, notes.Folder{Name: "   "})
+	assert.Error(t, err)
+	assert.Len(t, repo.created, 1)
+}
+
+func TestFolderService_GetTree(t *testing.T) {
+	repo := &fakeFolderRepo{
+		// As returned by the repository: parents before their subfolders.
+		folders: []notes.Folder{
+			{Id: 1, Name: "Home"},
+			{Id: 2, Name: "Work"},
+			{Id: 3, ParentId: intPointer(2), Name: "Project X"},
+			{Id: 4, ParentId: intPointer(3), Name: "Archive"},
+		},
+		lists: []notes.FolderedList{
+			{NotesList: notes.NotesList{Id: 10, Title: "groceries"}},
+			{NotesList: notes.NotesList{Id: 11, Title: "backlog"}, FolderId: intPointer(3)},
+			{NotesList: notes.NotesList{Id: 12, Title: "old"}, FolderId: intPointer(4)},
+			{NotesList: notes.NotesList{Id: 13, Title: "bugs"}, FolderId: intPointer(3)},
+			{NotesList: notes.NotesList{Id: 14, Title: "stray"}, FolderId: intPointer(99)},
+		},
+	}
+	s := NewFolderService(repo)
+
+	tree, err := s.GetTree(1)
+	assert.NoError(t, err)
+
+	empty := []notes.NotesList{}
+	assert.Equal(t, notes.FolderTree{
+		Folders: []notes.FolderNode{
+			{Folder: repo.folders[0], Folders: []notes.FolderNode{}, Lists: empty},
+			{Folder: repo.folders[1], Lists: empty, Folders: []notes.FolderNode{
+				{
+					Folder: repo.folders[2],
+					Folders: []notes.FolderNode{
+						{Folder: repo.folders[3], Folders: []notes.FolderNode{}, Lists: []notes.NotesList{{Id: 12, Title: "old"}}},
+					},
+					Lists: []notes.NotesList{{Id: 11, Title: "backlog"}, {Id: 13, Title: "bugs"}},
+				},
+			}},
+		},
+		Lists: []notes.NotesList{{Id: 10, Title: "groceries"}, {Id: 14, Title: "stray"}},
+	}, tree)
+}
+
+func TestFolderService_GetTreeEmpty(t *testing.T) {
+	s := NewFolderService(&fakeFolderRepo{})
+
+	tree, err := s.GetTree(1)
+	assert.NoError(t, err)
+	assert.Equal(t, notes.FolderTree{Folders: []notes.FolderNode{}, Lists: []notes.NotesList{}}, tree)
+}
+
+func intPointer(i int) *int {
+	return &i
+}
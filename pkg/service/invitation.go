@@ -0,0 +1,133 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// invitationAudience keeps invite tokens and access tokens, which are
+// signed with the same keys, from being accepted in place of each other.
+const invitationAudience = "invitation"
+
+type InvitationService struct {
+	repo     repository.Invitation
+	listRepo repository.NotesList
+	keys     *KeySet
+	ttl      time.Duration
+}
+
+func NewInvitationService(repo repository.Invitation, listRepo repository.NotesList, keys *KeySet, ttl time.Duration) *InvitationService {
+	return &InvitationService{
+		repo:     repo,
+		listRepo: listRepo,
+		keys:     keys,
+		ttl:      ttl,
+	}
+}
+
+// Invite creates a pending invitation to the list. Without a username it
+// creates a link invitation and returns it with a signed token that can be
+// redeemed by any user until it expires.
+func (s *InvitationService) Invite(userId, listId int, inp notes.InviteInput) (notes.Invitation, error) {
+	if err := inp.Validate(); err != nil {
+		return notes.Invitation{}, err
+	}
+
+	role, err := s.listRepo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return notes.Invitation{}, err
+	}
+
+	now := time.Now()
+	inv := notes.Invitation{
+		ListId:    listId,
+		Role:      inp.Role,
+		Status:    notes.InvitationPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if inp.Username != "" {
+		inv.Invitee = &inp.Username
+	}
+
+	inv.Id, err = s.repo.Create(userId, inv)
+	if err != nil {
+		return notes.Invitation{}, err
+	}
+
+	if inv.Invitee == nil {
+		inv.Token, err = s.keys.Sign(&jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(inv.Id),
+			Audience:  jwt.ClaimStrings{invitationAudience},
+			ExpiresAt: jwt.NewNumericDate(inv.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		})
+		if err != nil {
+			return notes.Invitation{}, err
+		}
+	}
+
+	return inv, nil
+}
+
+func (s *InvitationService) GetPending(userId int) ([]notes.Invitation, error) {
+	return s.repo.GetPending(userId)
+}
+
+func (s *InvitationService) GetListInvitations(userId, listId int) ([]notes.Invitation, error) {
+	role, err := s.listRepo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByList(listId)
+}
+
+func (s *InvitationService) Revoke(userId, listId, invitationId int) error {
+	role, err := s.listRepo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return err
+	}
+
+	return s.repo.Revoke(listId, invitationId)
+}
+
+// Accept joins the user to the list of an invitation addressed to them and
+// returns the list id.
+func (s *InvitationService) Accept(userId, invitationId int) (int, error) {
+	return s.repo.Accept(userId, invitationId)
+}
+
+// AcceptToken redeems a link invitation token for the user.
+func (s *InvitationService) AcceptToken(userId int, token string) (int, error) {
+	claims := &jwt.RegisteredClaims{}
+	if err := s.keys.Parse(token, claims); err != nil {
+		return -1, err
+	}
+
+	if !claims.VerifyAudience(invitationAudience, true) || claims.ExpiresAt == nil {
+		return -1, notes.ErrInvalidToken
+	}
+
+	invitationId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return -1, notes.ErrInvalidToken
+	}
+
+	return s.repo.AcceptLink(userId, invitationId)
+}
+
+func (s *InvitationService) Decline(userId, invitationId int) error {
+	return s.repo.Decline(userId, invitationId)
+}
+
+// expireInvitations is the body of the invitation sweeper job.
+func (s *InvitationService) expireInvitations() error {
+	_, err := s.repo.ExpirePending()
+
+	return err
+}
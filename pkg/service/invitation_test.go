@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInvitationRepo struct {
+	repository.Invitation
+	created  []notes.Invitation
+	accepted []int
+}
+
+func (r *fakeInvitationRepo) Create(inviterId int, inv notes.Invitation) (int, error) {
+	r.created = append(r.created, inv)
+	return len(r.created), nil
+}
+
+func (r *fakeInvitationRepo) AcceptLink(userId, invitationId int) (int, error) {
+	r.accepted = append(r.accepted, invitationId)
+	return r.created[invitationId-1].ListId, nil
+}
+
+func newTestInvitationService(role string) (*InvitationService, *fakeInvitationRepo) {
+	repo := &fakeInvitationRepo{}
+	listRepo := &fakeListRepo{roles: map[int]string{1: role}}
+
+	return NewInvitationService(repo, listRepo, newHMACKeySet("key"), time.Hour), repo
+}
+
+func TestInvitationService_Invite(t *testing.T) {
+	s, repo := newTestInvitationService(notes.RoleOwner)
+
+	inv, err := s.Invite(1, 2, notes.InviteInput{Username: "bob", Role: notes.RoleEditor})
+	assert.NoError(t, err)
+	assert.Empty(t, inv.Token)
+	assert.Equal(t, "bob", *repo.created[0].Invitee)
+
+	_, err = s.Invite(1, 2, notes.InviteInput{Role: "admin"})
+	assert.Error(t, err)
+
+	s, _ = newTestInvitationService(notes.RoleEditor)
+	_, err = s.Invite(1, 2, notes.InviteInput{Username: "bob", Role: notes.RoleViewer})
+	assert.ErrorIs(t, err, notes.ErrForbidden)
+}
+
+func TestInvitationService_AcceptToken(t *testing.T) {
+	s, repo := newTestInvitationService(notes.RoleOwner)
+
+	inv, err := s.Invite(1, 2, notes.InviteInput{Role: notes.RoleViewer})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inv.Token)
+
+	listId, err := s.AcceptToken(3, inv.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, listId)
+	assert.Equal(t, []int{inv.Id}, repo.accepted)
+
+	// An invite token must not pass as an access token and vice versa.
+	auth := NewAuthService(newFakeAuthRepo(), "salt", s.keys, time.Hour, time.Hour)
+	_, err = auth.ParseToken(inv.Token)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+
+	accessToken, err := auth.newAccessToken(3)
+	assert.NoError(t, err)
+	_, err = s.AcceptToken(3, accessToken)
+	assert.ErrorIs(t, err, notes.ErrInvalidToken)
+}
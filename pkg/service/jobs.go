@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is maintenance work the server runs in the background on a fixed
+// interval, such as expiring stale invitations.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Start runs the job every Interval until ctx is cancelled. A failed run is
+// logged and retried on the next tick; a job without an interval is
+// disabled.
+func (j Job) Start(ctx context.Context) {
+	if j.Interval <= 0 {
+		logrus.Warnf("job %s has no interval, not starting it", j.Name)
+		return
+	}
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.Run(); err != nil {
+				logrus.Errorf("job %s failed: %s", j.Name, err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/Liopun/notes-app"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// SigningKey is one JWT key identified by its kid. Keys loaded from a public
+// key PEM can only verify; they are kept around while tokens signed by a
+// retired private key are still valid.
+type SigningKey struct {
+	Id     string
+	Method jwt.SigningMethod
+
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// NewHMACKey wraps a shared secret as an HS256 key. HMAC keys are never
+// published in the JWKS.
+func NewHMACKey(id, secret string) *SigningKey {
+	return &SigningKey{
+		Id:      id,
+		Method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+}
+
+// LoadSigningKey reads an RSA or Ed25519 key from a PEM file. A private key
+// (PKCS#1 or PKCS#8) can sign and verify, a PKIX public key only verify.
+func LoadSigningKey(id, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data in %s", id, path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	key := &SigningKey{Id: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", id, parsed)
+	}
+
+	return key, nil
+}
+
+// KeySet signs tokens with its active key and verifies them with whichever
+// key the token's kid header names.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string
+}
+
+func NewKeySet(activeId string, keys ...*SigningKey) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+
+	for _, key := range keys {
+		if _, ok := set.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.Id)
+		}
+		set.keys[key.Id] = key
+		set.order = append(set.order, key.Id)
+	}
+
+	active, ok := set.keys[activeId]
+	if !ok {
+		return nil, fmt.Errorf("active key %q is not configured", activeId)
+	}
+
+	if active.private == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeId)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	token.Header["kid"] = k.active.Id
+
+	return token.SignedString(k.active.private)
+}
+
+// Parse verifies the token signature and fills claims. Tokens without a kid
+// predate key rotation and are checked against the HMAC key, if any.
+func (k *KeySet) Parse(tokenString string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := k.keys[kid]
+		if !ok && kid == "" {
+			key, ok = k.hmacKey()
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+
+		return key.public, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", notes.ErrInvalidToken, err.Error())
+	}
+
+	return nil
+}
+
+func (k *KeySet) hmacKey() (*SigningKey, bool) {
+	for _, id := range k.order {
+		if _, ok := k.keys[id].Method.(*jwt.SigningMethodHMAC); ok {
+			return k.keys[id], true
+		}
+	}
+
+	return nil, false
+}
+
+// JWKS returns the public half of every asymmetric key in the set.
+func (k *KeySet) JWKS() notes.JSONWebKeySet {
+	set := notes.JSONWebKeySet{Keys: make([]notes.JSONWebKey, 0, len(k.keys))}
+
+	for _, id := range k.order {
+		key := k.keys[id]
+		jwk := notes.JSONWebKey{
+			Kid: key.Id,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("error occured '%s' writing key file", err)
+	}
+
+	return path
+}
+
+func newTestClaims() *tokenClaims {
+	return &tokenClaims{
+		jwt.RegisteredClaims{
+			ID:        "jti",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		1,
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaPKCS8, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edPKCS8, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edPublic, _ := x509.MarshalPKIXPublicKey(edKey.Public())
+
+	tests := []struct {
+		name        string
+		path        string
+		wantAlg     string
+		wantPrivate bool
+		wantErr     bool
+	}{
+		{
+			name:        "RSA PKCS1",
+			path:        writePEM(t, "rsa1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+			wantAlg:     "RS256",
+			wantPrivate: true,
+		},
+		{
+			name:        "RSA PKCS8",
+			path:        writePEM(t, "rsa8.pem", "PRIVATE KEY", rsaPKCS8),
+			wantAlg:     "RS256",
+			wantPrivate: true,
+		},
+		{
+			name:        "Ed25519",
+			path:        writePEM(t, "ed.pem", "PRIVATE KEY", edPKCS8),
+			wantAlg:     "EdDSA",
+			wantPrivate: true,
+		},
+		{
+			name:    "Ed25519 Public",
+			path:    writePEM(t, "ed.pub", "PUBLIC KEY", edPublic),
+			wantAlg: "EdDSA",
+		},
+		{
+			name:    "Unsupported Block",
+			path:    writePEM(t, "cert.pem", "CERTIFICATE", []byte("junk")),
+			wantErr: true,
+		},
+		{
+			name:    "Missing File",
+			path:    filepath.Join(t.TempDir(), "missing.pem"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSigningKey("kid", tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, key.Method.Alg())
+			assert.Equal(t, tt.wantPrivate, key.private != nil)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edPKCS8, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edPublic, _ := x509.MarshalPKIXPublicKey(edKey.Public())
+
+	oldKey, err := LoadSigningKey("old", writePEM(t, "old.pem", "PRIVATE KEY", edPKCS8))
+	assert.NoError(t, err)
+	oldPublic, err := LoadSigningKey("old", writePEM(t, "old.pub", "PUBLIC KEY", edPublic))
+	assert.NoError(t, err)
+	newKey, err := LoadSigningKey("new", writePEM(t, "new.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)))
+	assert.NoError(t, err)
+	hmacKey := NewHMACKey("hs256", "secret")
+
+	before, err := NewKeySet("old", oldKey, hmacKey)
+	assert.NoError(t, err)
+	after, err := NewKeySet("new", newKey, oldPublic, hmacKey)
+	assert.NoError(t, err)
+
+	oldToken, err := before.Sign(newTestClaims())
+	assert.NoError(t, err)
+	newToken, err := after.Sign(newTestClaims())
+	assert.NoError(t, err)
+
+	// Tokens from the retired key stay valid while its public half is listed.
+	assert.NoError(t, after.Parse(oldToken, &tokenClaims{}))
+	assert.NoError(t, after.Parse(newToken, &tokenClaims{}))
+	assert.ErrorIs(t, before.Parse(newToken, &tokenClaims{}), notes.ErrInvalidToken)
+
+	// Tokens issued before kids existed fall back to the HMAC key.
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, newTestClaims()).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	assert.NoError(t, after.Parse(legacy, &tokenClaims{}))
+
+	// A token claiming an HMAC alg under an asymmetric kid is rejected.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, newTestClaims())
+	forged.Header["kid"] = "new"
+	forgedString, err := forged.SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, after.Parse(forgedString, &tokenClaims{}), notes.ErrInvalidToken)
+
+	jwks := after.JWKS()
+	if assert.Len(t, jwks.Keys, 2) {
+		assert.Equal(t, "new", jwks.Keys[0].Kid)
+		assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+		assert.Equal(t, "AQAB", jwks.Keys[0].E)
+		assert.Equal(t, "old", jwks.Keys[1].Kid)
+		assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+		assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	}
+}
+
+func TestNewKeySet_Invalid(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edPublic, _ := x509.MarshalPKIXPublicKey(edKey.Public())
+	public, err := LoadSigningKey("pub", writePEM(t, "ed.pub", "PUBLIC KEY", edPublic))
+	assert.NoError(t, err)
+
+	_, err = NewKeySet("missing", NewHMACKey("hs256", "secret"))
+	assert.Error(t, err)
+
+	_, err = NewKeySet("pub", public)
+	assert.Error(t, err)
+
+	_, err = NewKeySet("hs256", NewHMACKey("hs256", "a"), NewHMACKey("hs256", "b"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+type LinkService struct {
+	repo     repository.Link
+	itemRepo repository.NotesItem
+}
+
+func NewLinkService(repo repository.Link, itemRepo repository.NotesItem) *LinkService {
+	return &LinkService{
+		repo:     repo,
+		itemRepo: itemRepo,
+	}
+}
+
+// GetLinks returns the links of an item the user can read, with the reason
+// each broken one doesn't work.
+func (s *LinkService) GetLinks(userId, itemId int) ([]notes.ItemLink, error) {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetLinks(userId, itemId)
+}
+
+// GetBacklinks returns the items linking to an item the user can read.
+// Items of lists the user isn't a member of are left out.
+func (s *LinkService) GetBacklinks(userId, itemId int) ([]notes.ListedItem, error) {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBacklinks(userId, itemId)
+}
+
+func (s *LinkService) GetBroken(userId int) ([]notes.BrokenLink, error) {
+	return s.repo.GetBroken(userId)
+}
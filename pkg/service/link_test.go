@@ -0,0 +1,106 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLinkRepo struct {
+	repository.Link
+	calls int
+}
+
+func (r *fakeLinkRepo) GetBacklinks(userId, itemId int) ([]notes.ListedItem, error) {
+	r.calls++
+	return nil, nil
+}
+
+func TestParseLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "None", text: "plain [text] and [[ ]]"},
+		{name: "Titles And Ids", text: "see [[Groceries]] and [[#12]]", want: []string{"Groceries", "#12"}},
+		{name: "Duplicates", text: "[[ Groceries ]] [[groceries]] [[#012]] [[#12]]", want: []string{"Groceries", "#12"}},
+		{name: "Not Ids", text: "[[#]] [[#x]] [[#0]] [[#+3]] [[#99999999999]]"},
+		{name: "Not Across Lines", text: "[[Gro\nceries]]"},
+		{name: "Innermost Brackets", text: "[[[Groceries]]]", want: []string{"Groceries"}},
+		{name: "Too Long", text: "[[" + strings.Repeat("a", 256) + "]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notes.ParseLinks(tt.text))
+		})
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		ref   string
+		title string
+		want  string
+	}{
+		{name: "Renamed", text: "buy [[groceries]], then [[ Groceries ]]", ref: "Groceries", title: "Shopping", want: "buy [[Shopping]], then [[Shopping]]"},
+		{name: "Other Links", text: "[[Groceries list]] [[#4]]", ref: "Groceries", title: "Shopping", want: "[[Groceries list]] [[#4]]"},
+		{name: "Unlinkable Title", text: "[[Groceries]]", ref: "Groceries", title: "Shopping [soon]", want: "[[Groceries]]"},
+		{name: "Id Like Title", text: "[[Groceries]]", ref: "Groceries", title: "#4", want: "[[Groceries]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notes.RewriteLinks(tt.text, tt.ref, tt.title))
+		})
+	}
+}
+
+func TestNotesItem_ValidateLinks(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < notes.MaxItemLinks; i++ {
+		fmt.Fprintf(&b, "[[item %d]] ", i)
+	}
+
+	description := b.String()
+	assert.NoError(t, notes.NotesItem{Description: description}.Validate())
+
+	description += "[[one more]]"
+	assert.Error(t, notes.NotesItem{Description: description}.Validate())
+	assert.Error(t, notes.UpdateItemInput{Description: &description}.Validate())
+}
+
+func TestLinkService_GetBacklinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		itemId  int
+		wantErr error
+	}{
+		{name: "Viewer", itemId: 2},
+		{name: "Unknown Item", itemId: 3, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLinkRepo{}
+			s := NewLinkService(repo, &fakeItemRepo{roles: map[int]string{2: notes.RoleViewer}})
+
+			_, err := s.GetBacklinks(1, tt.itemId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Zero(t, repo.calls)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, repo.calls)
+			}
+		})
+	}
+}
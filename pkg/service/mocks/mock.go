@@ -34,6 +34,21 @@ func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
+// CreatePersonalToken mocks base method.
+func (m *MockAuthorization) CreatePersonalToken(userId int, inp notes_app.CreateTokenInput) (notes_app.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalToken", userId, inp)
+	ret0, _ := ret[0].(notes_app.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalToken indicates an expected call of CreatePersonalToken.
+func (mr *MockAuthorizationMockRecorder) CreatePersonalToken(userId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalToken", reflect.TypeOf((*MockAuthorization)(nil).CreatePersonalToken), userId, inp)
+}
+
 // CreateUser mocks base method.
 func (m *MockAuthorization) CreateUser(user notes_app.User) (int, error) {
 	m.ctrl.T.Helper()
@@ -50,10 +65,10 @@ func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Ca
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthorization) GenerateToken(username, password string) (string, error) {
+func (m *MockAuthorization) GenerateToken(username, password string) (notes_app.Tokens, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateToken", username, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(notes_app.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -64,6 +79,65 @@ func (mr *MockAuthorizationMockRecorder) GenerateToken(username, password interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), username, password)
 }
 
+// GetPersonalTokens mocks base method.
+func (m *MockAuthorization) GetPersonalTokens(userId int) ([]notes_app.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalTokens", userId)
+	ret0, _ := ret[0].([]notes_app.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalTokens indicates an expected call of GetPersonalTokens.
+func (mr *MockAuthorizationMockRecorder) GetPersonalTokens(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalTokens", reflect.TypeOf((*MockAuthorization)(nil).GetPersonalTokens), userId)
+}
+
+// JWKS mocks base method.
+func (m *MockAuthorization) JWKS() notes_app.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(notes_app.JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthorizationMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthorization)(nil).JWKS))
+}
+
+// Logout mocks base method.
+func (m *MockAuthorization) Logout(accessToken, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", accessToken, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthorizationMockRecorder) Logout(accessToken, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthorization)(nil).Logout), accessToken, refreshToken)
+}
+
+// ParsePersonalToken mocks base method.
+func (m *MockAuthorization) ParsePersonalToken(token string) (int, notes_app.Scopes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParsePersonalToken", token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(notes_app.Scopes)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ParsePersonalToken indicates an expected call of ParsePersonalToken.
+func (mr *MockAuthorizationMockRecorder) ParsePersonalToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParsePersonalToken", reflect.TypeOf((*MockAuthorization)(nil).ParsePersonalToken), token)
+}
+
 // ParseToken mocks base method.
 func (m *MockAuthorization) ParseToken(token string) (int, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +153,35 @@ func (mr *MockAuthorizationMockRecorder) ParseToken(token interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), token)
 }
 
+// RefreshToken mocks base method.
+func (m *MockAuthorization) RefreshToken(refreshToken string) (notes_app.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", refreshToken)
+	ret0, _ := ret[0].(notes_app.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthorizationMockRecorder) RefreshToken(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RefreshToken), refreshToken)
+}
+
+// RevokePersonalToken mocks base method.
+func (m *MockAuthorization) RevokePersonalToken(userId, tokenId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalToken", userId, tokenId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePersonalToken indicates an expected call of RevokePersonalToken.
+func (mr *MockAuthorizationMockRecorder) RevokePersonalToken(userId, tokenId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalToken", reflect.TypeOf((*MockAuthorization)(nil).RevokePersonalToken), userId, tokenId)
+}
+
 // MockNotesList is a mock of NotesList interface.
 type MockNotesList struct {
 	ctrl     *gomock.Controller
@@ -118,32 +221,33 @@ func (mr *MockNotesListMockRecorder) Create(userId, list interface{}) *gomock.Ca
 }
 
 // Delete mocks base method.
-func (m *MockNotesList) Delete(userId, listId int) error {
+func (m *MockNotesList) Delete(userId, listId, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userId, listId)
+	ret := m.ctrl.Call(m, "Delete", userId, listId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockNotesListMockRecorder) Delete(userId, listId interface{}) *gomock.Call {
+func (mr *MockNotesListMockRecorder) Delete(userId, listId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotesList)(nil).Delete), userId, listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotesList)(nil).Delete), userId, listId, version)
 }
 
 // GetAll mocks base method.
-func (m *MockNotesList) GetAll(userId int) ([]notes_app.NotesList, error) {
+func (m *MockNotesList) GetAll(userId int, q notes_app.ListsQuery) ([]notes_app.NotesList, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret := m.ctrl.Call(m, "GetAll", userId, q)
 	ret0, _ := ret[0].([]notes_app.NotesList)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockNotesListMockRecorder) GetAll(userId interface{}) *gomock.Call {
+func (mr *MockNotesListMockRecorder) GetAll(userId, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockNotesList)(nil).GetAll), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockNotesList)(nil).GetAll), userId, q)
 }
 
 // GetById mocks base method.
@@ -161,18 +265,90 @@ func (mr *MockNotesListMockRecorder) GetById(userId, listId interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockNotesList)(nil).GetById), userId, listId)
 }
 
-// Update mocks base method.
-func (m *MockNotesList) Update(userId, listId int, inp notes_app.UpdateListInput) error {
+// GetMembers mocks base method.
+func (m *MockNotesList) GetMembers(userId, listId int) ([]notes_app.ListMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", userId, listId)
+	ret0, _ := ret[0].([]notes_app.ListMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockNotesListMockRecorder) GetMembers(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockNotesList)(nil).GetMembers), userId, listId)
+}
+
+// Reorder mocks base method.
+func (m *MockNotesList) Reorder(userId, listId int, inp notes_app.ReorderInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", userId, listId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockNotesListMockRecorder) Reorder(userId, listId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockNotesList)(nil).Reorder), userId, listId, inp)
+}
+
+// Restore mocks base method.
+func (m *MockNotesList) Restore(userId, listId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", userId, listId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockNotesListMockRecorder) Restore(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockNotesList)(nil).Restore), userId, listId)
+}
+
+// Share mocks base method.
+func (m *MockNotesList) Share(userId, listId int, inp notes_app.ShareListInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", userId, listId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Share indicates an expected call of Share.
+func (mr *MockNotesListMockRecorder) Share(userId, listId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockNotesList)(nil).Share), userId, listId, inp)
+}
+
+// Unshare mocks base method.
+func (m *MockNotesList) Unshare(userId, listId int, username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userId, listId, inp)
+	ret := m.ctrl.Call(m, "Unshare", userId, listId, username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Unshare indicates an expected call of Unshare.
+func (mr *MockNotesListMockRecorder) Unshare(userId, listId, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unshare", reflect.TypeOf((*MockNotesList)(nil).Unshare), userId, listId, username)
+}
+
+// Update mocks base method.
+func (m *MockNotesList) Update(userId, listId int, inp notes_app.UpdateListInput, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, listId, inp, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Update indicates an expected call of Update.
-func (mr *MockNotesListMockRecorder) Update(userId, listId, inp interface{}) *gomock.Call {
+func (mr *MockNotesListMockRecorder) Update(userId, listId, inp, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotesList)(nil).Update), userId, listId, inp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotesList)(nil).Update), userId, listId, inp, version)
 }
 
 // MockNotesItem is a mock of NotesItem interface.
@@ -198,6 +374,21 @@ func (m *MockNotesItem) EXPECT() *MockNotesItemMockRecorder {
 	return m.recorder
 }
 
+// CopyItems mocks base method.
+func (m *MockNotesItem) CopyItems(userId int, inp notes_app.MoveItemsInput) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyItems", userId, inp)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyItems indicates an expected call of CopyItems.
+func (mr *MockNotesItemMockRecorder) CopyItems(userId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyItems", reflect.TypeOf((*MockNotesItem)(nil).CopyItems), userId, inp)
+}
+
 // Create mocks base method.
 func (m *MockNotesItem) Create(userId, listId int, item notes_app.NotesItem) (int, error) {
 	m.ctrl.T.Helper()
@@ -214,32 +405,48 @@ func (mr *MockNotesItemMockRecorder) Create(userId, listId, item interface{}) *g
 }
 
 // Delete mocks base method.
-func (m *MockNotesItem) Delete(userId, itemId int) error {
+func (m *MockNotesItem) Delete(userId, itemId, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userId, itemId)
+	ret := m.ctrl.Call(m, "Delete", userId, itemId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockNotesItemMockRecorder) Delete(userId, itemId interface{}) *gomock.Call {
+func (mr *MockNotesItemMockRecorder) Delete(userId, itemId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotesItem)(nil).Delete), userId, itemId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotesItem)(nil).Delete), userId, itemId, version)
 }
 
-// GetAll mocks base method.
-func (m *MockNotesItem) GetAll(userId, listId int) ([]notes_app.NotesItem, error) {
+// DiffRevisions mocks base method.
+func (m *MockNotesItem) DiffRevisions(userId, itemId, from, to int) (notes_app.RevisionDiff, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userId, listId)
-	ret0, _ := ret[0].([]notes_app.NotesItem)
+	ret := m.ctrl.Call(m, "DiffRevisions", userId, itemId, from, to)
+	ret0, _ := ret[0].(notes_app.RevisionDiff)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockNotesItemMockRecorder) DiffRevisions(userId, itemId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockNotesItem)(nil).DiffRevisions), userId, itemId, from, to)
+}
+
+// GetAll mocks base method.
+func (m *MockNotesItem) GetAll(userId, listId int, q notes_app.ItemsQuery) ([]notes_app.NotesItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, listId, q)
+	ret0, _ := ret[0].([]notes_app.NotesItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetAll indicates an expected call of GetAll.
-func (mr *MockNotesItemMockRecorder) GetAll(userId, listId interface{}) *gomock.Call {
+func (mr *MockNotesItemMockRecorder) GetAll(userId, listId, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockNotesItem)(nil).GetAll), userId, listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockNotesItem)(nil).GetAll), userId, listId, q)
 }
 
 // GetById mocks base method.
@@ -257,16 +464,875 @@ func (mr *MockNotesItemMockRecorder) GetById(userId, itemId interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockNotesItem)(nil).GetById), userId, itemId)
 }
 
-// Update mocks base method.
-func (m *MockNotesItem) Update(userId, itemId int, inp notes_app.UpdateItemInput) error {
+// GetDue mocks base method.
+func (m *MockNotesItem) GetDue(userId int, q notes_app.DueQuery) ([]notes_app.ListedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDue", userId, q)
+	ret0, _ := ret[0].([]notes_app.ListedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDue indicates an expected call of GetDue.
+func (mr *MockNotesItemMockRecorder) GetDue(userId, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDue", reflect.TypeOf((*MockNotesItem)(nil).GetDue), userId, q)
+}
+
+// GetRevision mocks base method.
+func (m *MockNotesItem) GetRevision(userId, itemId, version int) (notes_app.ItemRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", userId, itemId, version)
+	ret0, _ := ret[0].(notes_app.ItemRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockNotesItemMockRecorder) GetRevision(userId, itemId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockNotesItem)(nil).GetRevision), userId, itemId, version)
+}
+
+// GetRevisions mocks base method.
+func (m *MockNotesItem) GetRevisions(userId, itemId int) ([]notes_app.ItemRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.ItemRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockNotesItemMockRecorder) GetRevisions(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockNotesItem)(nil).GetRevisions), userId, itemId)
+}
+
+// MoveItems mocks base method.
+func (m *MockNotesItem) MoveItems(userId int, inp notes_app.MoveItemsInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveItems", userId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveItems indicates an expected call of MoveItems.
+func (mr *MockNotesItemMockRecorder) MoveItems(userId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveItems", reflect.TypeOf((*MockNotesItem)(nil).MoveItems), userId, inp)
+}
+
+// Reorder mocks base method.
+func (m *MockNotesItem) Reorder(userId, itemId int, inp notes_app.ReorderInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", userId, itemId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockNotesItemMockRecorder) Reorder(userId, itemId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockNotesItem)(nil).Reorder), userId, itemId, inp)
+}
+
+// Restore mocks base method.
+func (m *MockNotesItem) Restore(userId, itemId int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userId, itemId, inp)
+	ret := m.ctrl.Call(m, "Restore", userId, itemId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Restore indicates an expected call of Restore.
+func (mr *MockNotesItemMockRecorder) Restore(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockNotesItem)(nil).Restore), userId, itemId)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockNotesItem) RestoreRevision(userId, itemId, version, expectedVersion int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", userId, itemId, version, expectedVersion)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockNotesItemMockRecorder) RestoreRevision(userId, itemId, version, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockNotesItem)(nil).RestoreRevision), userId, itemId, version, expectedVersion)
+}
+
+// Update mocks base method.
+func (m *MockNotesItem) Update(userId, itemId int, inp notes_app.UpdateItemInput, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, itemId, inp, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Update indicates an expected call of Update.
-func (mr *MockNotesItemMockRecorder) Update(userId, itemId, inp interface{}) *gomock.Call {
+func (mr *MockNotesItemMockRecorder) Update(userId, itemId, inp, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotesItem)(nil).Update), userId, itemId, inp, version)
+}
+
+// MockRenderer is a mock of Renderer interface.
+type MockRenderer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRendererMockRecorder
+}
+
+// MockRendererMockRecorder is the mock recorder for MockRenderer.
+type MockRendererMockRecorder struct {
+	mock *MockRenderer
+}
+
+// NewMockRenderer creates a new mock instance.
+func NewMockRenderer(ctrl *gomock.Controller) *MockRenderer {
+	mock := &MockRenderer{ctrl: ctrl}
+	mock.recorder = &MockRendererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRenderer) EXPECT() *MockRendererMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockRenderer) Render(userId, itemId int) (notes_app.RenderedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", userId, itemId)
+	ret0, _ := ret[0].(notes_app.RenderedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockRendererMockRecorder) Render(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockRenderer)(nil).Render), userId, itemId)
+}
+
+// MockInvitation is a mock of Invitation interface.
+type MockInvitation struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationMockRecorder
+}
+
+// MockInvitationMockRecorder is the mock recorder for MockInvitation.
+type MockInvitationMockRecorder struct {
+	mock *MockInvitation
+}
+
+// NewMockInvitation creates a new mock instance.
+func NewMockInvitation(ctrl *gomock.Controller) *MockInvitation {
+	mock := &MockInvitation{ctrl: ctrl}
+	mock.recorder = &MockInvitationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitation) EXPECT() *MockInvitationMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockInvitation) Accept(userId, invitationId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", userId, invitationId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockInvitationMockRecorder) Accept(userId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockInvitation)(nil).Accept), userId, invitationId)
+}
+
+// AcceptToken mocks base method.
+func (m *MockInvitation) AcceptToken(userId int, token string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptToken", userId, token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptToken indicates an expected call of AcceptToken.
+func (mr *MockInvitationMockRecorder) AcceptToken(userId, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptToken", reflect.TypeOf((*MockInvitation)(nil).AcceptToken), userId, token)
+}
+
+// Decline mocks base method.
+func (m *MockInvitation) Decline(userId, invitationId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", userId, invitationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockInvitationMockRecorder) Decline(userId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockInvitation)(nil).Decline), userId, invitationId)
+}
+
+// GetListInvitations mocks base method.
+func (m *MockInvitation) GetListInvitations(userId, listId int) ([]notes_app.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListInvitations", userId, listId)
+	ret0, _ := ret[0].([]notes_app.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListInvitations indicates an expected call of GetListInvitations.
+func (mr *MockInvitationMockRecorder) GetListInvitations(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInvitations", reflect.TypeOf((*MockInvitation)(nil).GetListInvitations), userId, listId)
+}
+
+// GetPending mocks base method.
+func (m *MockInvitation) GetPending(userId int) ([]notes_app.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", userId)
+	ret0, _ := ret[0].([]notes_app.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockInvitationMockRecorder) GetPending(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockInvitation)(nil).GetPending), userId)
+}
+
+// Invite mocks base method.
+func (m *MockInvitation) Invite(userId, listId int, inp notes_app.InviteInput) (notes_app.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invite", userId, listId, inp)
+	ret0, _ := ret[0].(notes_app.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invite indicates an expected call of Invite.
+func (mr *MockInvitationMockRecorder) Invite(userId, listId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invite", reflect.TypeOf((*MockInvitation)(nil).Invite), userId, listId, inp)
+}
+
+// Revoke mocks base method.
+func (m *MockInvitation) Revoke(userId, listId, invitationId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", userId, listId, invitationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockInvitationMockRecorder) Revoke(userId, listId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockInvitation)(nil).Revoke), userId, listId, invitationId)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearch) Search(userId int, inp notes_app.SearchInput) ([]notes_app.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userId, inp)
+	ret0, _ := ret[0].([]notes_app.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchMockRecorder) Search(userId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearch)(nil).Search), userId, inp)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockTrash) GetAll(userId int) ([]notes_app.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]notes_app.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTrashMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTrash)(nil).GetAll), userId)
+}
+
+// MockTag is a mock of Tag interface.
+type MockTag struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagMockRecorder
+}
+
+// MockTagMockRecorder is the mock recorder for MockTag.
+type MockTagMockRecorder struct {
+	mock *MockTag
+}
+
+// NewMockTag creates a new mock instance.
+func NewMockTag(ctrl *gomock.Controller) *MockTag {
+	mock := &MockTag{ctrl: ctrl}
+	mock.recorder = &MockTagMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTag) EXPECT() *MockTagMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTag) Create(userId int, tag notes_app.Tag) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, tag)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagMockRecorder) Create(userId, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTag)(nil).Create), userId, tag)
+}
+
+// Delete mocks base method.
+func (m *MockTag) Delete(userId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagMockRecorder) Delete(userId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTag)(nil).Delete), userId, tagId)
+}
+
+// GetAll mocks base method.
+func (m *MockTag) GetAll(userId int) ([]notes_app.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]notes_app.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTag)(nil).GetAll), userId)
+}
+
+// GetItemTags mocks base method.
+func (m *MockTag) GetItemTags(userId, itemId int) ([]notes_app.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemTags", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemTags indicates an expected call of GetItemTags.
+func (mr *MockTagMockRecorder) GetItemTags(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemTags", reflect.TypeOf((*MockTag)(nil).GetItemTags), userId, itemId)
+}
+
+// GetItems mocks base method.
+func (m *MockTag) GetItems(userId int, q notes_app.TaggedItemsQuery) ([]notes_app.ListedItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", userId, q)
+	ret0, _ := ret[0].([]notes_app.ListedItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockTagMockRecorder) GetItems(userId, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockTag)(nil).GetItems), userId, q)
+}
+
+// Merge mocks base method.
+func (m *MockTag) Merge(userId, tagId int, inp notes_app.MergeTagInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", userId, tagId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTagMockRecorder) Merge(userId, tagId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTag)(nil).Merge), userId, tagId, inp)
+}
+
+// TagItem mocks base method.
+func (m *MockTag) TagItem(userId, itemId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagItem", userId, itemId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagItem indicates an expected call of TagItem.
+func (mr *MockTagMockRecorder) TagItem(userId, itemId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagItem", reflect.TypeOf((*MockTag)(nil).TagItem), userId, itemId, tagId)
+}
+
+// UntagItem mocks base method.
+func (m *MockTag) UntagItem(userId, itemId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagItem", userId, itemId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagItem indicates an expected call of UntagItem.
+func (mr *MockTagMockRecorder) UntagItem(userId, itemId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagItem", reflect.TypeOf((*MockTag)(nil).UntagItem), userId, itemId, tagId)
+}
+
+// Update mocks base method.
+func (m *MockTag) Update(userId, tagId int, inp notes_app.UpdateTagInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, tagId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagMockRecorder) Update(userId, tagId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTag)(nil).Update), userId, tagId, inp)
+}
+
+// MockChecklist is a mock of Checklist interface.
+type MockChecklist struct {
+	ctrl     *gomock.Controller
+	recorder *MockChecklistMockRecorder
+}
+
+// MockChecklistMockRecorder is the mock recorder for MockChecklist.
+type MockChecklistMockRecorder struct {
+	mock *MockChecklist
+}
+
+// NewMockChecklist creates a new mock instance.
+func NewMockChecklist(ctrl *gomock.Controller) *MockChecklist {
+	mock := &MockChecklist{ctrl: ctrl}
+	mock.recorder = &MockChecklistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChecklist) EXPECT() *MockChecklistMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockChecklist) Create(userId, itemId int, entry notes_app.ChecklistEntry) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, itemId, entry)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockChecklistMockRecorder) Create(userId, itemId, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChecklist)(nil).Create), userId, itemId, entry)
+}
+
+// Delete mocks base method.
+func (m *MockChecklist) Delete(userId, itemId, entryId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, itemId, entryId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockChecklistMockRecorder) Delete(userId, itemId, entryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChecklist)(nil).Delete), userId, itemId, entryId)
+}
+
+// GetAll mocks base method.
+func (m *MockChecklist) GetAll(userId, itemId int) ([]notes_app.ChecklistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.ChecklistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockChecklistMockRecorder) GetAll(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockChecklist)(nil).GetAll), userId, itemId)
+}
+
+// Reorder mocks base method.
+func (m *MockChecklist) Reorder(userId, itemId, entryId int, inp notes_app.ReorderInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", userId, itemId, entryId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockChecklistMockRecorder) Reorder(userId, itemId, entryId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockChecklist)(nil).Reorder), userId, itemId, entryId, inp)
+}
+
+// Update mocks base method.
+func (m *MockChecklist) Update(userId, itemId, entryId int, inp notes_app.UpdateChecklistEntryInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, itemId, entryId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockChecklistMockRecorder) Update(userId, itemId, entryId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChecklist)(nil).Update), userId, itemId, entryId, inp)
+}
+
+// MockLink is a mock of Link interface.
+type MockLink struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkMockRecorder
+}
+
+// MockLinkMockRecorder is the mock recorder for MockLink.
+type MockLinkMockRecorder struct {
+	mock *MockLink
+}
+
+// NewMockLink creates a new mock instance.
+func NewMockLink(ctrl *gomock.Controller) *MockLink {
+	mock := &MockLink{ctrl: ctrl}
+	mock.recorder = &MockLinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLink) EXPECT() *MockLinkMockRecorder {
+	return m.recorder
+}
+
+// GetBacklinks mocks base method.
+func (m *MockLink) GetBacklinks(userId, itemId int) ([]notes_app.ListedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBacklinks", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.ListedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBacklinks indicates an expected call of GetBacklinks.
+func (mr *MockLinkMockRecorder) GetBacklinks(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBacklinks", reflect.TypeOf((*MockLink)(nil).GetBacklinks), userId, itemId)
+}
+
+// GetBroken mocks base method.
+func (m *MockLink) GetBroken(userId int) ([]notes_app.BrokenLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBroken", userId)
+	ret0, _ := ret[0].([]notes_app.BrokenLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBroken indicates an expected call of GetBroken.
+func (mr *MockLinkMockRecorder) GetBroken(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBroken", reflect.TypeOf((*MockLink)(nil).GetBroken), userId)
+}
+
+// GetLinks mocks base method.
+func (m *MockLink) GetLinks(userId, itemId int) ([]notes_app.ItemLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinks", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.ItemLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinks indicates an expected call of GetLinks.
+func (mr *MockLinkMockRecorder) GetLinks(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinks", reflect.TypeOf((*MockLink)(nil).GetLinks), userId, itemId)
+}
+
+// MockFolder is a mock of Folder interface.
+type MockFolder struct {
+	ctrl     *gomock.Controller
+	recorder *MockFolderMockRecorder
+}
+
+// MockFolderMockRecorder is the mock recorder for MockFolder.
+type MockFolderMockRecorder struct {
+	mock *MockFolder
+}
+
+// NewMockFolder creates a new mock instance.
+func NewMockFolder(ctrl *gomock.Controller) *MockFolder {
+	mock := &MockFolder{ctrl: ctrl}
+	mock.recorder = &MockFolderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFolder) EXPECT() *MockFolderMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFolder) Create(userId int, folder notes_app.Folder) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, folder)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFolderMockRecorder) Create(userId, folder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFolder)(nil).Create), userId, folder)
+}
+
+// Delete mocks base method.
+func (m *MockFolder) Delete(userId, folderId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, folderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFolderMockRecorder) Delete(userId, folderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFolder)(nil).Delete), userId, folderId)
+}
+
+// GetTree mocks base method.
+func (m *MockFolder) GetTree(userId int) (notes_app.FolderTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTree", userId)
+	ret0, _ := ret[0].(notes_app.FolderTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTree indicates an expected call of GetTree.
+func (mr *MockFolderMockRecorder) GetTree(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTree", reflect.TypeOf((*MockFolder)(nil).GetTree), userId)
+}
+
+// Move mocks base method.
+func (m *MockFolder) Move(userId, folderId int, inp notes_app.MoveFolderInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", userId, folderId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockFolderMockRecorder) Move(userId, folderId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockFolder)(nil).Move), userId, folderId, inp)
+}
+
+// PlaceList mocks base method.
+func (m *MockFolder) PlaceList(userId, listId int, inp notes_app.PlaceListInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceList", userId, listId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PlaceList indicates an expected call of PlaceList.
+func (mr *MockFolderMockRecorder) PlaceList(userId, listId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceList", reflect.TypeOf((*MockFolder)(nil).PlaceList), userId, listId, inp)
+}
+
+// Update mocks base method.
+func (m *MockFolder) Update(userId, folderId int, inp notes_app.UpdateFolderInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, folderId, inp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFolderMockRecorder) Update(userId, folderId, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFolder)(nil).Update), userId, folderId, inp)
+}
+
+// MockReminder is a mock of Reminder interface.
+type MockReminder struct {
+	ctrl     *gomock.Controller
+	recorder *MockReminderMockRecorder
+}
+
+// MockReminderMockRecorder is the mock recorder for MockReminder.
+type MockReminderMockRecorder struct {
+	mock *MockReminder
+}
+
+// NewMockReminder creates a new mock instance.
+func NewMockReminder(ctrl *gomock.Controller) *MockReminder {
+	mock := &MockReminder{ctrl: ctrl}
+	mock.recorder = &MockReminderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminder) EXPECT() *MockReminderMockRecorder {
+	return m.recorder
+}
+
+// GetDeliveries mocks base method.
+func (m *MockReminder) GetDeliveries(userId, itemId int) ([]notes_app.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", userId, itemId)
+	ret0, _ := ret[0].([]notes_app.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockReminderMockRecorder) GetDeliveries(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockReminder)(nil).GetDeliveries), userId, itemId)
+}
+
+// MockInbox is a mock of Inbox interface.
+type MockInbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboxMockRecorder
+}
+
+// MockInboxMockRecorder is the mock recorder for MockInbox.
+type MockInboxMockRecorder struct {
+	mock *MockInbox
+}
+
+// NewMockInbox creates a new mock instance.
+func NewMockInbox(ctrl *gomock.Controller) *MockInbox {
+	mock := &MockInbox{ctrl: ctrl}
+	mock.recorder = &MockInboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInbox) EXPECT() *MockInboxMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockInbox) GetAll(userId int) ([]notes_app.InboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]notes_app.InboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockInboxMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockInbox)(nil).GetAll), userId)
+}
+
+// MarkRead mocks base method.
+func (m *MockInbox) MarkRead(userId, messageId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", userId, messageId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockInboxMockRecorder) MarkRead(userId, messageId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotesItem)(nil).Update), userId, itemId, inp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockInbox)(nil).MarkRead), userId, messageId)
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/Liopun/notes-app"
 	"github.com/Liopun/notes-app/pkg/repository"
 )
@@ -18,26 +20,253 @@ func NewNotesItemService(repo repository.NotesItem, listRepo repository.NotesLis
 }
 
 func (s *NotesItemService) Create(userId, listId int, item notes.NotesItem) (int, error) {
-	_, err := s.listRepo.GetById(userId, listId)
-	if err != nil {
+	if err := item.Validate(); err != nil {
+		return -1, err
+	}
+
+	role, err := s.listRepo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
 		return -1, err
 	}
 
-	return s.repo.Create(listId, item)
+	if item.Format == "" {
+		item.Format = notes.FormatPlain
+	}
+
+	item.Recurrence = canonicalRecurrence(item.Recurrence)
+	item.Occurrence = 1
+
+	return s.repo.Create(userId, listId, item)
 }
 
-func (s *NotesItemService) GetAll(userId, listId int) ([]notes.NotesItem, error) {
-	return s.repo.GetAll(userId, listId)
+func (s *NotesItemService) GetAll(userId, listId int, q notes.ItemsQuery) ([]notes.NotesItem, string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.GetAll(userId, listId, q)
 }
 
 func (s *NotesItemService) GetById(userId, itemId int) (notes.NotesItem, error) {
 	return s.repo.GetById(userId, itemId)
 }
 
-func (s *NotesItemService) Delete(userId, itemId int) error {
-	return s.repo.Delete(userId, itemId)
+// GetDue returns one of the due date views across all of the user's lists,
+// as of now.
+func (s *NotesItemService) GetDue(userId int, q notes.DueQuery) ([]notes.ListedItem, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	from, to := q.Range(time.Now())
+
+	return s.repo.GetDue(userId, from, to)
+}
+
+// Delete moves the item to the trash. A non-zero version makes the delete conditional
+// on the item not having changed since that version was read.
+func (s *NotesItemService) Delete(userId, itemId, version int) error {
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(userId, itemId, version)
 }
 
-func (s *NotesItemService) Update(userId, itemId int, inp notes.UpdateItemInput) error {
-	return s.repo.Update(userId, itemId, inp)
+// Update changes the item and returns its new version. A non-zero version
+// makes the update conditional on the item not having changed since.
+func (s *NotesItemService) Update(userId, itemId int, inp notes.UpdateItemInput, version int) (int, error) {
+	if err := inp.Validate(); err != nil {
+		return -1, err
+	}
+
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return -1, err
+	}
+
+	inp.Recurrence.String = canonicalRecurrence(inp.Recurrence.String)
+
+	return s.repo.Update(userId, itemId, inp, version, nextOccurrence)
+}
+
+// canonicalRecurrence rewrites a valid rule in canonical form.
+func canonicalRecurrence(rule *string) *string {
+	if rule == nil {
+		return nil
+	}
+
+	parsed, err := notes.ParseRecurrence(*rule)
+	if err != nil {
+		return rule
+	}
+
+	canonical := parsed.String()
+	return &canonical
+}
+
+// nextOccurrence is the item that follows a recurring item once it is
+// archived: a fresh copy due at the next date of the rule, with its
+// reminder just as far ahead of the due date. Items without a due date
+// start counting from now.
+func nextOccurrence(item notes.NotesItem) (notes.NotesItem, bool) {
+	rule, err := notes.ParseRecurrence(*item.Recurrence)
+	if err != nil {
+		return notes.NotesItem{}, false
+	}
+
+	prev := time.Now()
+	if item.DueAt != nil {
+		prev = *item.DueAt
+	}
+
+	dueAt, ok := rule.Next(prev, item.Occurrence)
+	if !ok {
+		return notes.NotesItem{}, false
+	}
+
+	next := notes.NotesItem{
+		Title:       item.Title,
+		Description: item.Description,
+		Format:      item.Format,
+		Priority:    item.Priority,
+		DueAt:       &dueAt,
+		Recurrence:  item.Recurrence,
+		Occurrence:  item.Occurrence + 1,
+	}
+
+	if item.RemindAt != nil && item.DueAt != nil {
+		remindAt := dueAt.Add(item.RemindAt.Sub(*item.DueAt))
+		next.RemindAt = &remindAt
+	}
+
+	return next, true
+}
+
+// Reorder moves the item within its list. The order of items is shared by
+// all members, so it takes an editor.
+func (s *NotesItemService) Reorder(userId, itemId int, inp notes.ReorderInput) error {
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Reorder(userId, itemId, inp)
+}
+
+// MoveItems moves a batch of items into another list. The user must be an
+// editor of the target list and of the lists the items come from.
+func (s *NotesItemService) MoveItems(userId int, inp notes.MoveItemsInput) error {
+	if err := s.checkBatch(userId, inp, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	return s.repo.Move(userId, inp.ListId, inp.ItemIds)
+}
+
+// CopyItems copies a batch of items into another list and returns the ids
+// of the copies. Reading the items is enough, but the user must be an
+// editor of the target list.
+func (s *NotesItemService) CopyItems(userId int, inp notes.MoveItemsInput) ([]int, error) {
+	if err := s.checkBatch(userId, inp, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Copy(userId, inp.ListId, inp.ItemIds)
+}
+
+// checkBatch validates a move or copy and checks that the user can edit
+// the target list and holds at least itemRole on every item.
+func (s *NotesItemService) checkBatch(userId int, inp notes.MoveItemsInput, itemRole string) error {
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	role, err := s.listRepo.GetRole(userId, inp.ListId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return err
+	}
+
+	for _, itemId := range inp.ItemIds {
+		role, err := s.repo.GetRole(userId, itemId)
+		if err := requireRole(role, err, itemRole); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore takes the item out of the trash. The repository checks that the
+// user can edit its list, since trashed items have no role to look up.
+func (s *NotesItemService) Restore(userId, itemId int) error {
+	return s.repo.Restore(userId, itemId)
+}
+
+func (s *NotesItemService) GetRevisions(userId, itemId int) ([]notes.ItemRevision, error) {
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRevisions(itemId)
+}
+
+func (s *NotesItemService) GetRevision(userId, itemId, version int) (notes.ItemRevision, error) {
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return notes.ItemRevision{}, err
+	}
+
+	return s.repo.GetRevision(itemId, version)
+}
+
+// DiffRevisions compares two revisions of an item line by line.
+func (s *NotesItemService) DiffRevisions(userId, itemId, from, to int) (notes.RevisionDiff, error) {
+	a, err := s.GetRevision(userId, itemId, from)
+	if err != nil {
+		return notes.RevisionDiff{}, err
+	}
+
+	b, err := s.repo.GetRevision(itemId, to)
+	if err != nil {
+		return notes.RevisionDiff{}, err
+	}
+
+	return notes.RevisionDiff{
+		From:         from,
+		To:           to,
+		Title:        diffLines(a.Title, b.Title),
+		Description:  diffLines(a.Description, b.Description),
+		ArchivedFrom: a.Archived,
+		ArchivedTo:   b.Archived,
+	}, nil
+}
+
+// RestoreRevision writes the content of an older revision back to the item,
+// which records it as a new revision, and returns the new version. A
+// non-zero expectedVersion makes the restore conditional like Update.
+func (s *NotesItemService) RestoreRevision(userId, itemId, version, expectedVersion int) (int, error) {
+	role, err := s.repo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return -1, err
+	}
+
+	revision, err := s.repo.GetRevision(itemId, version)
+	if err != nil {
+		return -1, err
+	}
+
+	// Restoring an archived revision puts history back; it doesn't advance
+	// a recurring series.
+	return s.repo.Update(userId, itemId, notes.UpdateItemInput{
+		Title:       &revision.Title,
+		Description: &revision.Description,
+		Archived:    &revision.Archived,
+	}, expectedVersion, nil)
 }
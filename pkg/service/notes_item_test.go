@@ -0,0 +1,249 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeItemRepo records which mutations reached the repository. Roles are
+// keyed by item id for a single user.
+type fakeItemRepo struct {
+	repository.NotesItem
+	roles map[int]string
+	calls []string
+
+	from, to *time.Time
+}
+
+func (r *fakeItemRepo) GetRole(userId, itemId int) (string, error) {
+	role, ok := r.roles[itemId]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (r *fakeItemRepo) GetDue(userId int, from, to *time.Time) ([]notes.ListedItem, error) {
+	r.from, r.to = from, to
+	return nil, nil
+}
+
+func (r *fakeItemRepo) Move(userId, targetListId int, itemIds []int) error {
+	r.calls = append(r.calls, "move")
+	return nil
+}
+
+func (r *fakeItemRepo) Copy(userId, targetListId int, itemIds []int) ([]int, error) {
+	r.calls = append(r.calls, "copy")
+	return itemIds, nil
+}
+
+func TestNotesItemService_MoveAndCopy(t *testing.T) {
+	tests := []struct {
+		name       string
+		listRole   string
+		itemRoles  map[int]string
+		run        func(s *NotesItemService) error
+		wantErr    error
+		wantCalled bool
+	}{
+		{
+			name:      "Move",
+			listRole:  notes.RoleEditor,
+			itemRoles: map[int]string{1: notes.RoleOwner, 2: notes.RoleEditor},
+			run: func(s *NotesItemService) error {
+				return s.MoveItems(1, notes.MoveItemsInput{ListId: 5, ItemIds: []int{1, 2}})
+			},
+			wantCalled: true,
+		},
+		{
+			name:      "Move From Viewed List",
+			listRole:  notes.RoleEditor,
+			itemRoles: map[int]string{1: notes.RoleEditor, 2: notes.RoleViewer},
+			run: func(s *NotesItemService) error {
+				return s.MoveItems(1, notes.MoveItemsInput{ListId: 5, ItemIds: []int{1, 2}})
+			},
+			wantErr: notes.ErrForbidden,
+		},
+		{
+			name:      "Move To Viewed List",
+			listRole:  notes.RoleViewer,
+			itemRoles: map[int]string{1: notes.RoleOwner},
+			run: func(s *NotesItemService) error {
+				return s.MoveItems(1, notes.MoveItemsInput{ListId: 5, ItemIds: []int{1}})
+			},
+			wantErr: notes.ErrForbidden,
+		},
+		{
+			name:      "Move Unknown Item",
+			listRole:  notes.RoleOwner,
+			itemRoles: map[int]string{1: notes.RoleOwner},
+			run: func(s *NotesItemService) error {
+				return s.MoveItems(1, notes.MoveItemsInput{ListId: 5, ItemIds: []int{1, 3}})
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:      "Copy From Viewed List",
+			listRole:  notes.RoleEditor,
+			itemRoles: map[int]string{1: notes.RoleViewer},
+			run: func(s *NotesItemService) error {
+				_, err := s.CopyItems(1, notes.MoveItemsInput{ListId: 5, ItemIds: []int{1}})
+				return err
+			},
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeItemRepo{roles: tt.itemRoles}
+			s := NewNotesItemService(repo, &fakeListRepo{roles: map[int]string{1: tt.listRole}})
+
+			err := tt.run(s)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalled, len(repo.calls) > 0)
+		})
+	}
+}
+
+func TestNotesItemService_GetDue(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		view     string
+		openFrom bool
+		days     int
+	}{
+		{name: "Overdue", view: notes.DueOverdue, openFrom: true},
+		{name: "Today", view: notes.DueToday, days: 1},
+		{name: "Upcoming", view: notes.DueUpcoming, days: notes.UpcomingDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeItemRepo{}
+			s := NewNotesItemService(repo, nil)
+
+			_, err := s.GetDue(1, notes.DueQuery{View: tt.view, Timezone: "Europe/Berlin"})
+			assert.NoError(t, err)
+
+			if tt.openFrom {
+				assert.Nil(t, repo.from)
+				assert.WithinDuration(t, time.Now(), *repo.to, time.Minute)
+				return
+			}
+
+			from := repo.from.In(berlin)
+			assert.Equal(t, 0, from.Hour()+from.Minute()+from.Second(), "days start at midnight in the given time zone")
+			assert.Equal(t, from.AddDate(0, 0, tt.days), *repo.to)
+			assert.False(t, from.After(time.Now().In(berlin).AddDate(0, 0, 1)))
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	// Monday, 5 October 2026.
+	monday := time.Date(2026, 10, 5, 9, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		rule       string
+		dueAt      time.Time
+		occurrence int
+		want       time.Time
+		wantEnded  bool
+	}{
+		{name: "Daily", rule: "FREQ=DAILY", dueAt: monday, want: day(10, 6)},
+		{name: "Every Third Day", rule: "FREQ=DAILY;INTERVAL=3", dueAt: monday, want: day(10, 8)},
+		{name: "Weekdays", rule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", dueAt: day(10, 9), want: day(10, 12)},
+		{name: "Weekly", rule: "FREQ=WEEKLY", dueAt: monday, want: day(10, 12)},
+		{name: "Later This Week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", dueAt: monday, want: day(10, 8)},
+		{name: "Skipped Week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", dueAt: day(10, 8), want: day(10, 19)},
+		{name: "Sunday Ends The Week", rule: "FREQ=WEEKLY;BYDAY=SU,MO", dueAt: monday, want: day(10, 11)},
+		{name: "Monthly", rule: "FREQ=MONTHLY", dueAt: monday, want: day(11, 5)},
+		{name: "Monthly Skips Short Months", rule: "FREQ=MONTHLY", dueAt: day(10, 31), want: day(12, 31)},
+		{name: "First Monday", rule: "FREQ=MONTHLY;BYDAY=1MO", dueAt: monday, want: day(11, 2)},
+		{name: "Last Friday", rule: "FREQ=MONTHLY;BYDAY=-1FR", dueAt: monday, want: day(10, 30)},
+		{name: "Count Left", rule: "FREQ=DAILY;COUNT=3", dueAt: monday, occurrence: 2, want: day(10, 6)},
+		{name: "Count Reached", rule: "FREQ=DAILY;COUNT=3", dueAt: monday, occurrence: 3, wantEnded: true},
+		{name: "Until Date", rule: "FREQ=DAILY;UNTIL=20261006", dueAt: monday, want: day(10, 6)},
+		{name: "Until Passed", rule: "FREQ=WEEKLY;UNTIL=20261010T000000Z", dueAt: monday, wantEnded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remindAt := tt.dueAt.Add(-time.Hour)
+			occurrence := tt.occurrence
+			if occurrence == 0 {
+				occurrence = 1
+			}
+
+			next, ok := nextOccurrence(notes.NotesItem{
+				Title:      "standup",
+				Priority:   notes.PriorityHigh,
+				Done:       true,
+				DueAt:      &tt.dueAt,
+				RemindAt:   &remindAt,
+				Recurrence: &tt.rule,
+				Occurrence: occurrence,
+			})
+			if tt.wantEnded {
+				assert.False(t, ok)
+				return
+			}
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, *next.DueAt)
+			assert.Equal(t, tt.want.Add(-time.Hour), *next.RemindAt)
+			assert.Equal(t, occurrence+1, next.Occurrence)
+			assert.Equal(t, "standup", next.Title)
+			assert.Equal(t, notes.PriorityHigh, next.Priority)
+			assert.False(t, next.Done)
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	for rule, want := range map[string]string{
+		"RRULE:freq=weekly;byday=mo,we;interval=1": "FREQ=WEEKLY;BYDAY=MO,WE",
+		"FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=6":      "FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=6",
+		"FREQ=DAILY;UNTIL=20261231T120000Z":        "FREQ=DAILY;UNTIL=20261231T120000Z",
+	} {
+		got := canonicalRecurrence(&rule)
+		assert.Equal(t, want, *got, rule)
+	}
+
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261231",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := notes.ParseRecurrence(rule)
+		assert.Error(t, err, rule)
+	}
+}
@@ -17,22 +17,106 @@ func (s *NotesListService) Create(userId int, list notes.NotesList) (int, error)
 	return s.repo.Create(userId, list)
 }
 
-func (s *NotesListService) GetAll(userId int) ([]notes.NotesList, error) {
-	return s.repo.GetAll(userId)
+func (s *NotesListService) GetAll(userId int, q notes.ListsQuery) ([]notes.NotesList, string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.GetAll(userId, q)
 }
 
 func (s *NotesListService) GetById(userId, listId int) (notes.NotesList, error) {
 	return s.repo.GetById(userId, listId)
 }
 
-func (s *NotesListService) Delete(userId, listId int) error {
-	return s.repo.Delete(userId, listId)
+// Delete moves the list to the trash. A non-zero version makes the delete conditional
+// on the list not having changed since that version was read.
+func (s *NotesListService) Delete(userId, listId, version int) error {
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(userId, listId, version)
+}
+
+// Update changes the list and returns its new version. A non-zero version
+// makes the update conditional on the list not having changed since.
+func (s *NotesListService) Update(userId, listId int, inp notes.UpdateListInput, version int) (int, error) {
+	if err := inp.Validate(); err != nil {
+		return -1, err
+	}
+
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleEditor); err != nil {
+		return -1, err
+	}
+
+	return s.repo.Update(userId, listId, inp, version)
+}
+
+func (s *NotesListService) GetMembers(userId, listId int) ([]notes.ListMember, error) {
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMembers(listId)
+}
+
+func (s *NotesListService) Share(userId, listId int, inp notes.ShareListInput) error {
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return err
+	}
+
+	return s.repo.Share(userId, listId, inp.Username, inp.Role)
+}
+
+func (s *NotesListService) Unshare(userId, listId int, username string) error {
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleOwner); err != nil {
+		return err
+	}
+
+	return s.repo.Unshare(userId, listId, username)
 }
 
-func (s *NotesListService) Update(userId, listId int, inp notes.UpdateListInput) error {
+// Reorder moves the list within the user's own order of lists, which any
+// member may arrange as they like.
+func (s *NotesListService) Reorder(userId, listId int, inp notes.ReorderInput) error {
 	if err := inp.Validate(); err != nil {
 		return err
 	}
 
-	return s.repo.Update(userId, listId, inp)
+	role, err := s.repo.GetRole(userId, listId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return err
+	}
+
+	return s.repo.Reorder(userId, listId, inp)
+}
+
+// Restore takes the list out of the trash. The repository checks that the
+// user owns it, since trashed lists have no role to look up.
+func (s *NotesListService) Restore(userId, listId int) error {
+	return s.repo.Restore(userId, listId)
+}
+
+// requireRole takes the result of a GetRole lookup and fails with
+// notes.ErrForbidden unless the role grants at least required.
+func requireRole(role string, err error, required string) error {
+	if err != nil {
+		return err
+	}
+
+	if !notes.RoleAllows(role, required) {
+		return notes.ErrForbidden
+	}
+
+	return nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeListRepo records which mutations reached the repository. Roles are
+// keyed by user id for a single list.
+type fakeListRepo struct {
+	repository.NotesList
+	roles map[int]string
+	calls []string
+}
+
+func (r *fakeListRepo) GetRole(userId, listId int) (string, error) {
+	role, ok := r.roles[userId]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (r *fakeListRepo) Update(userId, listId int, inp notes.UpdateListInput, version int) (int, error) {
+	r.calls = append(r.calls, "update")
+	return version + 1, nil
+}
+
+func (r *fakeListRepo) Delete(userId, listId, version int) error {
+	r.calls = append(r.calls, "delete")
+	return nil
+}
+
+func (r *fakeListRepo) Share(userId, listId int, username, role string) error {
+	r.calls = append(r.calls, "share")
+	return nil
+}
+
+func TestNotesListService_Roles(t *testing.T) {
+	title := "title"
+
+	tests := []struct {
+		name    string
+		role    string
+		run     func(s *NotesListService) error
+		wantErr error
+	}{
+		{
+			name: "Viewer Update",
+			role: notes.RoleViewer,
+			run: func(s *NotesListService) error {
+				_, err := s.Update(1, 1, notes.UpdateListInput{Title: &title}, 0)
+				return err
+			},
+			wantErr: notes.ErrForbidden,
+		},
+		{
+			name: "Editor Update",
+			role: notes.RoleEditor,
+			run: func(s *NotesListService) error {
+				_, err := s.Update(1, 1, notes.UpdateListInput{Title: &title}, 0)
+				return err
+			},
+		},
+		{
+			name: "Editor Delete",
+			role: notes.RoleEditor,
+			run: func(s *NotesListService) error {
+				return s.Delete(1, 1, 0)
+			},
+			wantErr: notes.ErrForbidden,
+		},
+		{
+			name: "Owner Delete",
+			role: notes.RoleOwner,
+			run: func(s *NotesListService) error {
+				return s.Delete(1, 1, 0)
+			},
+		},
+		{
+			name: "Editor Share",
+			role: notes.RoleEditor,
+			run: func(s *NotesListService) error {
+				return s.Share(1, 1, notes.ShareListInput{Username: "bob", Role: notes.RoleViewer})
+			},
+			wantErr: notes.ErrForbidden,
+		},
+		{
+			name: "Owner Share",
+			role: notes.RoleOwner,
+			run: func(s *NotesListService) error {
+				return s.Share(1, 1, notes.ShareListInput{Username: "bob", Role: notes.RoleEditor})
+			},
+		},
+		{
+			name: "Not A Member",
+			run: func(s *NotesListService) error {
+				return s.Delete(1, 1, 0)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeListRepo{roles: map[int]string{}}
+			if tt.role != "" {
+				repo.roles[1] = tt.role
+			}
+
+			err := tt.run(NewNotesListService(repo))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.calls)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, repo.calls, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+// Notifier delivers a reminder through one channel. A Notifier returns an
+// error wrapped with Permanent when retrying can't help, for instance when
+// the recipient has no email address; other errors are retried.
+type Notifier interface {
+	Notify(ctx context.Context, r notes.Reminder) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var perm permanentError
+	return errors.As(err, &perm)
+}
+
+// maxSubject is the length of inbox_messages.title, in characters.
+const maxSubject = 255
+
+// reminderSubject is the one-line summary of the reminder used as the
+// email subject and inbox title. Long titles are cut to fit maxSubject.
+func reminderSubject(r notes.Reminder) string {
+	subject := []rune("Reminder: " + r.Title)
+	if len(subject) > maxSubject {
+		return string(subject[:maxSubject-1]) + "…"
+	}
+
+	return string(subject)
+}
+
+func reminderBody(r notes.Reminder) string {
+	var b strings.Builder
+	b.WriteString(r.Title)
+	b.WriteString("\n")
+	if r.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(r.Description)
+		b.WriteString("\n")
+	}
+	if r.DueAt != nil {
+		fmt.Fprintf(&b, "\nDue %s\n", r.DueAt.UTC().Format(time.RFC1123))
+	}
+
+	return b.String()
+}
+
+// InboxNotifier posts reminders to the recipient's in-app inbox.
+type InboxNotifier struct {
+	repo repository.Inbox
+}
+
+func NewInboxNotifier(repo repository.Inbox) *InboxNotifier {
+	return &InboxNotifier{repo: repo}
+}
+
+func (n *InboxNotifier) Notify(ctx context.Context, r notes.Reminder) error {
+	itemId := r.ItemId
+
+	return n.repo.Create(r.DeliveryId, notes.InboxMessage{
+		UserId: r.UserId,
+		ItemId: &itemId,
+		Title:  reminderSubject(r),
+		Body:   reminderBody(r),
+	})
+}
+
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPNotifier emails reminders to the recipient's address. The connection
+// is upgraded with STARTTLS whenever the server offers it, and credentials
+// are only sent when a username is configured.
+type SMTPNotifier struct {
+	cfg SMTPConfig
+	// rootCAs verifies the server certificate; nil means the system roots.
+	rootCAs *x509.CertPool
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, r notes.Reminder) error {
+	if r.Email == nil || *r.Email == "" {
+		return Permanent(fmt.Errorf("user %s has no email address", r.Username))
+	}
+
+	host, _, err := net.SplitHostPort(n.cfg.Addr)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, RootCAs: n.rootCAs}); err != nil {
+			return err
+		}
+	}
+
+	if n.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(*r.Email); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.message(r)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (n *SMTPNotifier) message(r notes.Reminder) []byte {
+	// Item titles are user input; keep them from injecting headers.
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(reminderSubject(r))
+	body := strings.ReplaceAll(reminderBody(r), "\n", "\r\n")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", *r.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(body)
+
+	return b.Bytes()
+}
+
+// WebhookNotifier POSTs reminders as JSON to a fixed URL. Client errors
+// other than timeouts and rate limiting are not retried.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: http.DefaultClient,
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, r notes.Reminder) error {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("webhook responded with %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+
+	return err
+}
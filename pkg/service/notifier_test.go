@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInboxRepo struct {
+	repository.Inbox
+	created []notes.InboxMessage
+}
+
+func (r *fakeInboxRepo) Create(deliveryId int, msg notes.InboxMessage) error {
+	r.created = append(r.created, msg)
+	return nil
+}
+
+func TestInboxNotifier_Notify(t *testing.T) {
+	repo := &fakeInboxRepo{}
+	n := NewInboxNotifier(repo)
+
+	assert.NoError(t, n.Notify(context.Background(), notes.Reminder{ItemId: 4, UserId: 1, Title: "pay rent"}))
+	assert.Equal(t, "Reminder: pay rent", repo.created[0].Title)
+
+	// Titles may be as long as the subject column, multi-byte ones included.
+	assert.NoError(t, n.Notify(context.Background(), notes.Reminder{ItemId: 4, UserId: 1, Title: strings.Repeat("é", 255)}))
+	title := []rune(repo.created[1].Title)
+	assert.Len(t, title, maxSubject)
+	assert.Equal(t, "Reminder: éé", string(title[:12]))
+	assert.Equal(t, '…', title[maxSubject-1])
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "OK", status: http.StatusNoContent},
+		{name: "Rejected", status: http.StatusGone, wantErr: true, wantPermanent: true},
+		{name: "Rate Limited", status: http.StatusTooManyRequests, wantErr: true},
+		{name: "Server Error", status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got notes.Reminder
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := NewWebhookNotifier(srv.URL).Notify(context.Background(), notes.Reminder{DeliveryId: 3, ItemId: 5, Title: "item"})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPermanent, isPermanent(err))
+			assert.Equal(t, notes.Reminder{DeliveryId: 3, ItemId: 5, Title: "item"}, got)
+		})
+	}
+}
+
+// serveSMTP accepts a single SMTP session on l and returns the message
+// data it received. With a TLS config the server offers STARTTLS.
+func serveSMTP(t *testing.T, l net.Listener, config *tls.Config) <-chan string {
+	data := make(chan string, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { conn.Close() }()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				if _, ok := conn.(*tls.Conn); config != nil && !ok {
+					reply("250-localhost")
+					reply("250 STARTTLS")
+				} else {
+					reply("250 localhost")
+				}
+			case cmd == "STARTTLS":
+				reply("220 ready")
+				conn = tls.Server(conn, config)
+				r = bufio.NewReader(conn)
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var msg strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					msg.WriteString(line)
+				}
+				data <- msg.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	return data
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	data := serveSMTP(t, l, nil)
+
+	email := "alice@example.com"
+	dueAt := time.Date(2026, 10, 2, 17, 0, 0, 0, time.UTC)
+	n := NewSMTPNotifier(SMTPConfig{Addr: l.Addr().String(), From: "notes@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = n.Notify(ctx, notes.Reminder{Title: "pay\r\nBcc: eve@example.com", Description: "rent", DueAt: &dueAt, Username: "alice", Email: &email})
+	assert.NoError(t, err)
+
+	header, body, _ := strings.Cut(<-data, "\r\n\r\n")
+	assert.Contains(t, header, "To: alice@example.com\r\n")
+	assert.Contains(t, header, "Subject: Reminder: pay  Bcc: eve@example.com\r\n")
+	assert.NotContains(t, header, "\r\nBcc:")
+	assert.Contains(t, body, "\r\nrent\r\n")
+	assert.Contains(t, body, "Due Fri, 02 Oct 2026 17:00:00 UTC")
+}
+
+func TestSMTPNotifier_NotifyStartTLS(t *testing.T) {
+	// The httptest certificate is issued for 127.0.0.1 and example.com.
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(srv.Certificate())
+
+	tests := []struct {
+		name    string
+		rootCAs *x509.CertPool
+		wantErr bool
+	}{
+		{name: "OK", rootCAs: trusted},
+		{name: "Untrusted Certificate", rootCAs: x509.NewCertPool(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err)
+			}
+			defer l.Close()
+
+			data := serveSMTP(t, l, &tls.Config{Certificates: srv.TLS.Certificates})
+
+			email := "alice@example.com"
+			n := NewSMTPNotifier(SMTPConfig{Addr: l.Addr().String(), From: "notes@example.com"})
+			n.rootCAs = tt.rootCAs
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = n.Notify(ctx, notes.Reminder{Title: "pay rent", Username: "alice", Email: &email})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Contains(t, <-data, "Subject: Reminder: pay rent\r\n")
+		})
+	}
+}
+
+func TestSMTPNotifier_NotifyWithoutEmail(t *testing.T) {
+	err := NewSMTPNotifier(SMTPConfig{Addr: "127.0.0.1:25"}).Notify(context.Background(), notes.Reminder{Username: "alice"})
+
+	assert.Error(t, err)
+	assert.True(t, isPermanent(err))
+}
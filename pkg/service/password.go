@@ -0,0 +1,138 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// argon2id parameters for newly hashed passwords. Stored hashes carry their
+// own parameters, so raising these only affects new and rehashed passwords.
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 2
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// dummyHash is verified against when signing in as an unknown user. It is
+// hashed on first use, with the current parameters.
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// hashPassword returns a PHC-formatted argon2id hash with a random salt:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argon2Memory,
+		argon2Time,
+		argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// verifyDummyPassword takes as long as verifying password against an
+// argon2id hash, so that a failed sign-in doesn't reveal whether the
+// username exists.
+func verifyDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = hashPassword("")
+	})
+
+	verifyArgon2id(password, dummyHash)
+}
+
+// verifyPassword checks password against a stored hash. Besides argon2id it
+// accepts bcrypt and the legacy unsalted SHA-1 scheme; needsRehash reports
+// whether the stored hash should be replaced with a fresh argon2id one.
+func verifyPassword(password, encoded, legacySalt string) (ok, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return verifyArgon2id(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		return err == nil, err == nil, err
+	case strings.HasPrefix(encoded, "$"):
+		return false, false, errMalformedHash
+	default:
+		legacy := generateLegacyHash(password, legacySalt)
+		ok := subtle.ConstantTimeCompare([]byte(legacy), []byte(encoded)) == 1
+		return ok, ok, nil
+	}
+}
+
+func verifyArgon2id(password, encoded string) (ok, needsRehash bool, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, errMalformedHash
+	}
+
+	var (
+		memory  uint32
+		time    uint32
+		threads uint8
+	)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, errMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, errMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, errMalformedHash
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash = memory != argon2Memory || time != argon2Time || threads != argon2Threads || len(key) != argon2KeyLen
+
+	return true, needsRehash, nil
+}
+
+// generateLegacyHash reproduces the original SHA-1 scheme, which appended
+// the salt to the digest output instead of hashing it. It is only used to
+// verify users who have not signed in since the switch to argon2id.
+func generateLegacyHash(password, salt string) string {
+	hash := sha1.New()
+	hash.Write([]byte(password))
+
+	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword(t *testing.T) {
+	first, err := hashPassword("qwerty")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "$argon2id$v=19$m=65536,t=3,p=2$"))
+
+	second, err := hashPassword("qwerty")
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second, "salts must be random per hash")
+}
+
+func TestVerifyPassword(t *testing.T) {
+	argon2Hash, _ := hashPassword("qwerty")
+	salt := []byte("saltsaltsaltsalt")
+	weakArgon2Hash := "$argon2id$v=19$m=1024,t=1,p=1$" +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" +
+		base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte("qwerty"), salt, 1, 1024, 1, 32))
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("qwerty"), bcrypt.MinCost)
+
+	tests := []struct {
+		name            string
+		password        string
+		encoded         string
+		wantOk          bool
+		wantNeedsRehash bool
+		wantErr         bool
+	}{
+		{
+			name:     "Argon2id",
+			password: "qwerty",
+			encoded:  argon2Hash,
+			wantOk:   true,
+		},
+		{
+			name:     "Argon2id Mismatch",
+			password: "wrong",
+			encoded:  argon2Hash,
+		},
+		{
+			name:            "Argon2id Outdated Params",
+			password:        "qwerty",
+			encoded:         weakArgon2Hash,
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Bcrypt",
+			password:        "qwerty",
+			encoded:         string(bcryptHash),
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "Legacy SHA-1",
+			password:        "qwerty",
+			encoded:         generateLegacyHash("qwerty", "salt"),
+			wantOk:          true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:     "Legacy SHA-1 Mismatch",
+			password: "wrong",
+			encoded:  generateLegacyHash("qwerty", "salt"),
+		},
+		{
+			name:     "Malformed",
+			password: "qwerty",
+			encoded:  "$argon2id$broken",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := verifyPassword(tt.password, tt.encoded, "salt")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantNeedsRehash, needsRehash)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type ReminderConfig struct {
+	// BatchSize caps how many items are enqueued and how many deliveries
+	// are sent per run.
+	BatchSize int
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry; it doubles with
+	// every attempt up to maxRetryBackoff.
+	RetryBackoff time.Duration
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+}
+
+const maxRetryBackoff = 6 * time.Hour
+
+type ReminderService struct {
+	repo      repository.Reminder
+	itemRepo  repository.NotesItem
+	notifiers map[string]Notifier
+	cfg       ReminderConfig
+	now       func() time.Time
+}
+
+// NewReminderService returns the reminder service. Reminders go out through
+// every channel in notifiers; without notifiers nothing is sent.
+func NewReminderService(repo repository.Reminder, itemRepo repository.NotesItem, notifiers map[string]Notifier, cfg ReminderConfig) *ReminderService {
+	return &ReminderService{
+		repo:      repo,
+		itemRepo:  itemRepo,
+		notifiers: notifiers,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// GetDeliveries returns the user's reminders of an item they can read.
+func (s *ReminderService) GetDeliveries(userId, itemId int) ([]notes.ReminderDelivery, error) {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeliveries(userId, itemId)
+}
+
+func (s *ReminderService) channels() []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for channel := range s.notifiers {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	return channels
+}
+
+// send is the body of the reminder job. It enqueues deliveries for items
+// whose reminder time has come and then sends the deliveries that are due,
+// concurrently, so that a batch takes at most one Timeout. Claims are
+// leased for twice that, after which deliveries of a crashed server are
+// sent again.
+func (s *ReminderService) send() error {
+	if len(s.notifiers) == 0 {
+		return nil
+	}
+
+	enqueued, err := s.repo.Enqueue(s.channels(), s.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	if enqueued > 0 {
+		logrus.Infof("enqueued %d reminder deliveries", enqueued)
+	}
+
+	reminders, err := s.repo.Claim(s.cfg.BatchSize, 2*s.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range reminders {
+		wg.Add(1)
+		go func(r notes.Reminder) {
+			defer wg.Done()
+			if err := s.deliver(r); err != nil {
+				logrus.Errorf("failed to record reminder delivery %d: %s", r.DeliveryId, err.Error())
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver sends one reminder and records the outcome.
+func (s *ReminderService) deliver(r notes.Reminder) error {
+	notifier, ok := s.notifiers[r.Channel]
+	if !ok {
+		// The channel was configured when the delivery was enqueued but
+		// isn't anymore.
+		return s.repo.MarkFailed(r.DeliveryId, "channel "+r.Channel+" is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+
+	err := notifier.Notify(ctx, r)
+	if err == nil {
+		return s.repo.MarkSent(r.DeliveryId)
+	}
+
+	if isPermanent(err) || r.Attempts >= s.cfg.MaxAttempts {
+		logrus.Warnf("giving up on reminder delivery %d via %s: %s", r.DeliveryId, r.Channel, err.Error())
+		return s.repo.MarkFailed(r.DeliveryId, err.Error())
+	}
+
+	return s.repo.Retry(r.DeliveryId, err.Error(), s.now().Add(s.backoff(r.Attempts)))
+}
+
+// backoff is the delay before retrying a delivery that failed its
+// attempts-th attempt.
+func (s *ReminderService) backoff(attempts int) time.Duration {
+	delay := s.cfg.RetryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+
+	return delay
+}
+
+type InboxService struct {
+	repo repository.Inbox
+}
+
+func NewInboxService(repo repository.Inbox) *InboxService {
+	return &InboxService{repo: repo}
+}
+
+func (s *InboxService) GetAll(userId int) ([]notes.InboxMessage, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *InboxService) MarkRead(userId, messageId int) error {
+	return s.repo.MarkRead(userId, messageId)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReminderRepo hands out its claimable reminders once and records the
+// outcome of every delivery.
+type fakeReminderRepo struct {
+	repository.Reminder
+	claimable []notes.Reminder
+
+	mu       sync.Mutex
+	channels []string
+	lease    time.Duration
+	outcomes map[int]string
+	retryAt  map[int]time.Time
+}
+
+func (r *fakeReminderRepo) Enqueue(channels []string, limit int) (int64, error) {
+	r.channels = channels
+	return 0, nil
+}
+
+func (r *fakeReminderRepo) Claim(limit int, lease time.Duration) ([]notes.Reminder, error) {
+	r.lease = lease
+	claimed := r.claimable
+	r.claimable = nil
+	return claimed, nil
+}
+
+func (r *fakeReminderRepo) record(deliveryId int, outcome string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.outcomes == nil {
+		r.outcomes = make(map[int]string)
+	}
+	r.outcomes[deliveryId] = outcome
+}
+
+func (r *fakeReminderRepo) MarkSent(deliveryId int) error {
+	r.record(deliveryId, notes.DeliverySent)
+	return nil
+}
+
+func (r *fakeReminderRepo) MarkFailed(deliveryId int, reason string) error {
+	r.record(deliveryId, notes.DeliveryFailed)
+	return nil
+}
+
+func (r *fakeReminderRepo) Retry(deliveryId int, reason string, at time.Time) error {
+	r.record(deliveryId, notes.DeliveryPending)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.retryAt == nil {
+		r.retryAt = make(map[int]time.Time)
+	}
+	r.retryAt[deliveryId] = at
+
+	return nil
+}
+
+type notifierFunc func(ctx context.Context, r notes.Reminder) error
+
+func (f notifierFunc) Notify(ctx context.Context, r notes.Reminder) error {
+	return f(ctx, r)
+}
+
+func TestReminderService_send(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	failing := errors.New("connection refused")
+
+	repo := &fakeReminderRepo{claimable: []notes.Reminder{
+		{DeliveryId: 1, Channel: notes.ChannelInbox, Attempts: 1},
+		{DeliveryId: 2, Channel: notes.ChannelWebhook, Attempts: 1},
+		{DeliveryId: 3, Channel: notes.ChannelWebhook, Attempts: 3},
+		{DeliveryId: 4, Channel: notes.ChannelEmail, Attempts: 1},
+		{DeliveryId: 5, Channel: notes.ChannelWebhook, Attempts: 2},
+	}}
+
+	s := NewReminderService(repo, nil, map[string]Notifier{
+		notes.ChannelInbox: notifierFunc(func(ctx context.Context, r notes.Reminder) error {
+			return nil
+		}),
+		notes.ChannelWebhook: notifierFunc(func(ctx context.Context, r notes.Reminder) error {
+			if r.DeliveryId == 5 {
+				return Permanent(failing)
+			}
+			return failing
+		}),
+	}, ReminderConfig{BatchSize: 10, MaxAttempts: 3, RetryBackoff: time.Minute, Timeout: time.Second})
+	s.now = func() time.Time { return now }
+
+	assert.NoError(t, s.send())
+
+	assert.Equal(t, []string{notes.ChannelInbox, notes.ChannelWebhook}, repo.channels)
+	assert.Equal(t, 2*time.Second, repo.lease)
+	assert.Equal(t, map[int]string{
+		1: notes.DeliverySent,
+		2: notes.DeliveryPending,
+		3: notes.DeliveryFailed, // out of attempts
+		4: notes.DeliveryFailed, // channel no longer configured
+		5: notes.DeliveryFailed, // permanent error
+	}, repo.outcomes)
+	assert.Equal(t, map[int]time.Time{2: now.Add(time.Minute)}, repo.retryAt)
+}
+
+func TestReminderService_sendWithoutNotifiers(t *testing.T) {
+	repo := &fakeReminderRepo{claimable: []notes.Reminder{{DeliveryId: 1}}}
+
+	assert.NoError(t, NewReminderService(repo, nil, nil, ReminderConfig{}).send())
+	assert.Nil(t, repo.channels)
+	assert.Empty(t, repo.outcomes)
+}
+
+func TestReminderService_backoff(t *testing.T) {
+	s := NewReminderService(nil, nil, nil, ReminderConfig{RetryBackoff: time.Minute})
+
+	assert.Equal(t, time.Minute, s.backoff(1))
+	assert.Equal(t, 2*time.Minute, s.backoff(2))
+	assert.Equal(t, 8*time.Minute, s.backoff(4))
+	assert.Equal(t, maxRetryBackoff, s.backoff(20))
+}
+
+func TestReminderService_GetDeliveries(t *testing.T) {
+	s := NewReminderService(&fakeReminderRepo{}, &fakeItemRepo{roles: map[int]string{}}, nil, ReminderConfig{})
+
+	_, err := s.GetDeliveries(1, 5)
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"container/list"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown renders CommonMark with the GitHub extensions: tables, task
+// lists, strikethrough and autolinks. Raw HTML in descriptions is left out.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// htmlPolicy is the allow-list every rendered description passes through,
+// so that nothing a description holds can script the page it is shown on.
+var htmlPolicy = newHTMLPolicy()
+
+func newHTMLPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// Task list items are rendered as disabled checkboxes.
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return p
+}
+
+type RenderService struct {
+	repo  repository.NotesItem
+	cache *renderCache
+}
+
+// NewRenderService returns the render service, which keeps up to
+// cacheSize rendered descriptions in memory; 0 disables the cache.
+func NewRenderService(repo repository.NotesItem, cacheSize int) *RenderService {
+	return &RenderService{
+		repo:  repo,
+		cache: newRenderCache(cacheSize),
+	}
+}
+
+// Render returns the description of an item the user can read as HTML.
+// Every change to an item bumps its version, so renders are cached by
+// item version and never go stale.
+func (s *RenderService) Render(userId, itemId int) (notes.RenderedItem, error) {
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return notes.RenderedItem{}, err
+	}
+
+	key := renderKey{itemId: item.Id, version: item.Version}
+	out, ok := s.cache.get(key)
+	if !ok {
+		out, err = renderDescription(item)
+		if err != nil {
+			return notes.RenderedItem{}, err
+		}
+		s.cache.add(key, out)
+	}
+
+	return notes.RenderedItem{
+		ItemId:  item.Id,
+		Version: item.Version,
+		Format:  item.Format,
+		HTML:    out,
+	}, nil
+}
+
+func renderDescription(item notes.NotesItem) (string, error) {
+	if item.Format != notes.FormatMarkdown {
+		return renderPlain(item.Description), nil
+	}
+
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte(item.Description), &b); err != nil {
+		return "", err
+	}
+
+	return htmlPolicy.SanitizeReader(&b).String(), nil
+}
+
+// renderPlain escapes text and keeps its paragraphs and line breaks.
+func renderPlain(text string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+
+	return b.String()
+}
+
+type renderKey struct {
+	itemId  int
+	version int
+}
+
+type renderEntry struct {
+	key  renderKey
+	html string
+}
+
+// renderCache keeps the most recently used renders, up to size.
+type renderCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[renderKey]*list.Element
+}
+
+func newRenderCache(size int) *renderCache {
+	return &renderCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[renderKey]*list.Element),
+	}
+}
+
+func (c *renderCache) get(key renderKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+
+	return e.Value.(*renderEntry).html, true
+}
+
+func (c *renderCache) add(key renderKey, html string) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&renderEntry{key: key, html: html})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderEntry).key)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRenderRepo serves a single item and counts how often it is fetched.
+type fakeRenderRepo struct {
+	repository.NotesItem
+	item  notes.NotesItem
+	calls int
+}
+
+func (r *fakeRenderRepo) GetById(userId, itemId int) (notes.NotesItem, error) {
+	r.calls++
+	if itemId != r.item.Id {
+		return notes.NotesItem{}, sql.ErrNoRows
+	}
+
+	return r.item, nil
+}
+
+func TestRenderDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     notes.NotesItem
+		want     []string
+		wantNone []string
+	}{
+		{
+			name: "Plain",
+			item: notes.NotesItem{Format: notes.FormatPlain, Description: "milk & <eggs>\nbread\n\n\n**not bold**"},
+			want: []string{"<p>milk &amp; &lt;eggs&gt;<br>\nbread</p>\n<p>**not bold**</p>\n"},
+		},
+		{
+			name: "Markdown",
+			item: notes.NotesItem{Format: notes.FormatMarkdown, Description: "# Plan\n\n- [x] milk\n- [ ] eggs\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n~~old~~ **new**"},
+			want: []string{
+				"<h1>Plan</h1>",
+				`<input checked="" disabled="" type="checkbox"> milk`,
+				`<input disabled="" type="checkbox"> eggs`,
+				"<table>",
+				"<td>2</td>",
+				"<del>old</del>",
+				"<strong>new</strong>",
+			},
+		},
+		{
+			name:     "Markdown Sanitized",
+			item:     notes.NotesItem{Format: notes.FormatMarkdown, Description: "<script>alert(1)</script>\n\n[x](javascript:alert(1)) <img src=x onerror=alert(1)>\n\n[ok](https://example.com)"},
+			want:     []string{`<a href="https://example.com" rel="nofollow">ok</a>`},
+			wantNone: []string{"<script", "javascript:", "onerror"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderDescription(tt.item)
+			assert.NoError(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+			for _, none := range tt.wantNone {
+				assert.NotContains(t, got, none)
+			}
+		})
+	}
+}
+
+func TestRenderService_Render(t *testing.T) {
+	repo := &fakeRenderRepo{item: notes.NotesItem{Id: 4, Format: notes.FormatMarkdown, Description: "**a**", Version: 1}}
+	s := NewRenderService(repo, 10)
+
+	got, err := s.Render(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, notes.RenderedItem{ItemId: 4, Version: 1, Format: notes.FormatMarkdown, HTML: "<p><strong>a</strong></p>\n"}, got)
+
+	// The cached render is kept while the version stays the same.
+	repo.item.Description = "**b**"
+	got, err = s.Render(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><strong>a</strong></p>\n", got.HTML)
+
+	repo.item.Version = 2
+	got, err = s.Render(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, notes.RenderedItem{ItemId: 4, Version: 2, Format: notes.FormatMarkdown, HTML: "<p><strong>b</strong></p>\n"}, got)
+	assert.Equal(t, 3, repo.calls)
+
+	_, err = s.Render(1, 5)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRenderCache(t *testing.T) {
+	c := newRenderCache(2)
+	c.add(renderKey{1, 1}, "a")
+	c.add(renderKey{2, 1}, "b")
+	_, _ = c.get(renderKey{1, 1})
+	c.add(renderKey{3, 1}, "c")
+
+	_, ok := c.get(renderKey{2, 1})
+	assert.False(t, ok, "least recently used entry is evicted")
+	for key, want := range map[renderKey]string{{1, 1}: "a", {3, 1}: "c"} {
+		got, ok := c.get(key)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+
+	disabled := newRenderCache(0)
+	disabled.add(renderKey{1, 1}, "a")
+	_, ok = disabled.get(renderKey{1, 1})
+	assert.False(t, ok)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(userId int, inp notes.SearchInput) ([]notes.SearchResult, error) {
+	if err := inp.Validate(); err != nil {
+		return nil, err
+	}
+
+	if inp.Limit == 0 {
+		inp.Limit = notes.DefaultSearchLimit
+	}
+
+	return s.repo.Search(userId, inp.Query, inp.Limit)
+}
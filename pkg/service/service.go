@@ -11,47 +11,177 @@ import (
 
 type Authorization interface {
 	CreateUser(user notes.User) (int, error)
-	GenerateToken(username, password string) (string, error)
+	GenerateToken(username, password string) (notes.Tokens, error)
+	RefreshToken(refreshToken string) (notes.Tokens, error)
+	Logout(accessToken, refreshToken string) error
 	ParseToken(token string) (int, error)
+	JWKS() notes.JSONWebKeySet
+	CreatePersonalToken(userId int, inp notes.CreateTokenInput) (notes.PersonalAccessToken, error)
+	GetPersonalTokens(userId int) ([]notes.PersonalAccessToken, error)
+	RevokePersonalToken(userId, tokenId int) error
+	ParsePersonalToken(token string) (int, notes.Scopes, error)
 }
 
 type NotesList interface {
 	Create(userId int, list notes.NotesList) (int, error)
-	GetAll(userId int) ([]notes.NotesList, error)
+	GetAll(userId int, q notes.ListsQuery) ([]notes.NotesList, string, error)
 	GetById(userId, listId int) (notes.NotesList, error)
-	Delete(userId, listId int) error
-	Update(userId, listId int, inp notes.UpdateListInput) error
+	Delete(userId, listId, version int) error
+	Update(userId, listId int, inp notes.UpdateListInput, version int) (int, error)
+	GetMembers(userId, listId int) ([]notes.ListMember, error)
+	Share(userId, listId int, inp notes.ShareListInput) error
+	Unshare(userId, listId int, username string) error
+	Restore(userId, listId int) error
+	Reorder(userId, listId int, inp notes.ReorderInput) error
 }
 
 type NotesItem interface {
 	Create(userId, listId int, item notes.NotesItem) (int, error)
-	GetAll(userId, listId int) ([]notes.NotesItem, error)
+	GetAll(userId, listId int, q notes.ItemsQuery) ([]notes.NotesItem, string, error)
 	GetById(userId, itemId int) (notes.NotesItem, error)
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, inp notes.UpdateItemInput) error
+	GetDue(userId int, q notes.DueQuery) ([]notes.ListedItem, error)
+	Delete(userId, itemId, version int) error
+	Update(userId, itemId int, inp notes.UpdateItemInput, version int) (int, error)
+	GetRevisions(userId, itemId int) ([]notes.ItemRevision, error)
+	GetRevision(userId, itemId, version int) (notes.ItemRevision, error)
+	DiffRevisions(userId, itemId, from, to int) (notes.RevisionDiff, error)
+	RestoreRevision(userId, itemId, version, expectedVersion int) (int, error)
+	Restore(userId, itemId int) error
+	MoveItems(userId int, inp notes.MoveItemsInput) error
+	CopyItems(userId int, inp notes.MoveItemsInput) ([]int, error)
+	Reorder(userId, itemId int, inp notes.ReorderInput) error
+}
+
+type Renderer interface {
+	Render(userId, itemId int) (notes.RenderedItem, error)
+}
+
+type Invitation interface {
+	Invite(userId, listId int, inp notes.InviteInput) (notes.Invitation, error)
+	GetPending(userId int) ([]notes.Invitation, error)
+	GetListInvitations(userId, listId int) ([]notes.Invitation, error)
+	Revoke(userId, listId, invitationId int) error
+	Accept(userId, invitationId int) (int, error)
+	AcceptToken(userId int, token string) (int, error)
+	Decline(userId, invitationId int) error
+}
+
+type Search interface {
+	Search(userId int, inp notes.SearchInput) ([]notes.SearchResult, error)
+}
+
+type Trash interface {
+	GetAll(userId int) ([]notes.TrashEntry, error)
+}
+
+type Tag interface {
+	Create(userId int, tag notes.Tag) (int, error)
+	GetAll(userId int) ([]notes.Tag, error)
+	Update(userId, tagId int, inp notes.UpdateTagInput) error
+	Delete(userId, tagId int) error
+	Merge(userId, tagId int, inp notes.MergeTagInput) error
+	GetItemTags(userId, itemId int) ([]notes.Tag, error)
+	TagItem(userId, itemId, tagId int) error
+	UntagItem(userId, itemId, tagId int) error
+	GetItems(userId int, q notes.TaggedItemsQuery) ([]notes.ListedItem, string, error)
+}
+
+type Checklist interface {
+	Create(userId, itemId int, entry notes.ChecklistEntry) (int, error)
+	GetAll(userId, itemId int) ([]notes.ChecklistEntry, error)
+	Update(userId, itemId, entryId int, inp notes.UpdateChecklistEntryInput) error
+	Delete(userId, itemId, entryId int) error
+	Reorder(userId, itemId, entryId int, inp notes.ReorderInput) error
+}
+
+type Link interface {
+	GetLinks(userId, itemId int) ([]notes.ItemLink, error)
+	GetBacklinks(userId, itemId int) ([]notes.ListedItem, error)
+	GetBroken(userId int) ([]notes.BrokenLink, error)
+}
+
+type Folder interface {
+	Create(userId int, folder notes.Folder) (int, error)
+	Update(userId, folderId int, inp notes.UpdateFolderInput) error
+	Delete(userId, folderId int) error
+	Move(userId, folderId int, inp notes.MoveFolderInput) error
+	PlaceList(userId, listId int, inp notes.PlaceListInput) error
+	GetTree(userId int) (notes.FolderTree, error)
+}
+
+type Reminder interface {
+	GetDeliveries(userId, itemId int) ([]notes.ReminderDelivery, error)
+}
+
+type Inbox interface {
+	GetAll(userId int) ([]notes.InboxMessage, error)
+	MarkRead(userId, messageId int) error
 }
 
 type Service struct {
 	Authorization
 	NotesList
 	NotesItem
+	Renderer
+	Invitation
+	Search
+	Trash
+	Tag
+	Checklist
+	Link
+	Folder
+	Reminder
+	Inbox
+
+	// Jobs are started by the server process and stopped on shutdown.
+	Jobs []Job
 }
 
 type Deps struct {
-	Repos        *repository.Repository
-	PasswordSalt string
-	TokenTTL     time.Duration
-	SigningKey   string
+	Repos                   *repository.Repository
+	PasswordSalt            string
+	TokenTTL                time.Duration
+	RefreshTokenTTL         time.Duration
+	Keys                    *KeySet
+	InvitationTTL           time.Duration
+	InvitationSweepInterval time.Duration
+	TrashRetention          time.Duration
+	TrashPurgeInterval      time.Duration
+	// Notifiers are the channels reminders are delivered through, keyed
+	// by channel name.
+	Notifiers            map[string]Notifier
+	ReminderConfig       ReminderConfig
+	ReminderPollInterval time.Duration
+	// RenderCacheSize is how many rendered descriptions are kept in memory.
+	RenderCacheSize int
 }
 
 func NewService(deps Deps) *Service {
-	authService := NewAuthService(deps.Repos.Authorization, deps.PasswordSalt, deps.SigningKey, deps.TokenTTL)
+	authService := NewAuthService(deps.Repos.Authorization, deps.PasswordSalt, deps.Keys, deps.TokenTTL, deps.RefreshTokenTTL)
 	notesListService := NewNotesListService(deps.Repos.NotesList)
 	notesItemService := NewNotesItemService(deps.Repos.NotesItem, deps.Repos.NotesList)
+	invitationService := NewInvitationService(deps.Repos.Invitation, deps.Repos.NotesList, deps.Keys, deps.InvitationTTL)
+	trashService := NewTrashService(deps.Repos.Trash, deps.TrashRetention)
+	reminderService := NewReminderService(deps.Repos.Reminder, deps.Repos.NotesItem, deps.Notifiers, deps.ReminderConfig)
 
 	return &Service{
 		Authorization: authService,
 		NotesList:     notesListService,
 		NotesItem:     notesItemService,
+		Renderer:      NewRenderService(deps.Repos.NotesItem, deps.RenderCacheSize),
+		Invitation:    invitationService,
+		Search:        NewSearchService(deps.Repos.Search),
+		Trash:         trashService,
+		Tag:           NewTagService(deps.Repos.Tag, deps.Repos.NotesItem),
+		Checklist:     NewChecklistService(deps.Repos.Checklist, deps.Repos.NotesItem),
+		Link:          NewLinkService(deps.Repos.Link, deps.Repos.NotesItem),
+		Folder:        NewFolderService(deps.Repos.Folder),
+		Reminder:      reminderService,
+		Inbox:         NewInboxService(deps.Repos.Inbox),
+		Jobs: []Job{
+			{Name: "expire-invitations", Interval: deps.InvitationSweepInterval, Run: invitationService.expireInvitations},
+			{Name: "purge-trash", Interval: deps.TrashPurgeInterval, Run: trashService.purge},
+			{Name: "send-reminders", Interval: deps.ReminderPollInterval, Run: reminderService.send},
+		},
 	}
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+)
+
+type TagService struct {
+	repo     repository.Tag
+	itemRepo repository.NotesItem
+}
+
+func NewTagService(repo repository.Tag, itemRepo repository.NotesItem) *TagService {
+	return &TagService{
+		repo:     repo,
+		itemRepo: itemRepo,
+	}
+}
+
+func (s *TagService) Create(userId int, tag notes.Tag) (int, error) {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if err := tag.Validate(); err != nil {
+		return -1, err
+	}
+
+	if tag.Color == "" {
+		tag.Color = notes.DefaultTagColor
+	}
+
+	return s.repo.Create(userId, tag)
+}
+
+func (s *TagService) GetAll(userId int) ([]notes.Tag, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *TagService) Update(userId, tagId int, inp notes.UpdateTagInput) error {
+	if inp.Name != nil {
+		name := strings.TrimSpace(*inp.Name)
+		inp.Name = &name
+	}
+
+	if err := inp.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(userId, tagId, inp)
+}
+
+func (s *TagService) Delete(userId, tagId int) error {
+	return s.repo.Delete(userId, tagId)
+}
+
+// Merge folds the tag into the one named by inp, which keeps its name and
+// color and gains the items of the merged tag.
+func (s *TagService) Merge(userId, tagId int, inp notes.MergeTagInput) error {
+	return s.repo.Merge(userId, tagId, inp.IntoId)
+}
+
+// GetItemTags returns the user's tags on the item. Tags are personal, so
+// reading the item is enough.
+func (s *TagService) GetItemTags(userId, itemId int) ([]notes.Tag, error) {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetItemTags(userId, itemId)
+}
+
+// TagItem puts one of the user's tags on an item they can read.
+func (s *TagService) TagItem(userId, itemId, tagId int) error {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetById(userId, tagId); err != nil {
+		return err
+	}
+
+	return s.repo.TagItem(userId, itemId, tagId)
+}
+
+func (s *TagService) UntagItem(userId, itemId, tagId int) error {
+	role, err := s.itemRepo.GetRole(userId, itemId)
+	if err := requireRole(role, err, notes.RoleViewer); err != nil {
+		return err
+	}
+
+	return s.repo.UntagItem(userId, itemId, tagId)
+}
+
+// GetItems finds items by their tags across all of the user's lists. Item
+// positions only order items within one list, so unless asked otherwise
+// the result is sorted by last update.
+func (s *TagService) GetItems(userId int, q notes.TaggedItemsQuery) ([]notes.ListedItem, string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	if q.Sort == "" {
+		q.Sort = notes.SortUpdated
+	}
+
+	return s.repo.GetItems(userId, q)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTagRepo holds the tags of a single user, keyed by id.
+type fakeTagRepo struct {
+	repository.Tag
+	tags    map[int]notes.Tag
+	created []notes.Tag
+	tagged  []int
+	query   notes.TaggedItemsQuery
+}
+
+func (r *fakeTagRepo) Create(userId int, tag notes.Tag) (int, error) {
+	r.created = append(r.created, tag)
+	return len(r.created), nil
+}
+
+func (r *fakeTagRepo) GetById(userId, tagId int) (notes.Tag, error) {
+	tag, ok := r.tags[tagId]
+	if !ok {
+		return notes.Tag{}, sql.ErrNoRows
+	}
+
+	return tag, nil
+}
+
+func (r *fakeTagRepo) TagItem(userId, itemId, tagId int) error {
+	r.tagged = append(r.tagged, itemId)
+	return nil
+}
+
+func (r *fakeTagRepo) GetItems(userId int, q notes.TaggedItemsQuery) ([]notes.ListedItem, string, error) {
+	r.query = q
+	return nil, "", nil
+}
+
+func TestTagService_Create(t *testing.T) {
+	repo := &fakeTagRepo{}
+	s := NewTagService(repo, &fakeItemRepo{})
+
+	_, err := s.Create(1, notes.Tag{Name: "  work "})
+	assert.NoError(t, err)
+	assert.Equal(t, notes.Tag{Name: "work", Color: notes.DefaultTagColor}, repo.created[0])
+
+	_, err = s.Create(1, notes.Tag{Name: "work", Color: "red"})
+	assert.Error(t, err)
+	assert.Len(t, repo.created, 1)
+}
+
+func TestTagService_TagItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		itemId  int
+		tagId   int
+		wantErr error
+	}{
+		{name: "Viewer", itemId: 1, tagId: 5},
+		{name: "No Access", itemId: 2, tagId: 5, wantErr: sql.ErrNoRows},
+		{name: "Foreign Tag", itemId: 1, tagId: 6, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTagRepo{tags: map[int]notes.Tag{5: {Id: 5, Name: "work"}}}
+			s := NewTagService(repo, &fakeItemRepo{roles: map[int]string{1: notes.RoleViewer}})
+
+			err := s.TagItem(1, tt.itemId, tt.tagId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.tagged)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []int{tt.itemId}, repo.tagged)
+			}
+		})
+	}
+}
+
+func TestTagService_GetItems(t *testing.T) {
+	repo := &fakeTagRepo{}
+	s := NewTagService(repo, &fakeItemRepo{})
+
+	_, _, err := s.GetItems(1, notes.TaggedItemsQuery{TagIds: []int{5}})
+	assert.NoError(t, err)
+	assert.Equal(t, notes.SortUpdated, repo.query.Sort)
+
+	_, _, err = s.GetItems(1, notes.TaggedItemsQuery{TagIds: []int{5}, PageQuery: notes.PageQuery{Sort: notes.SortTitle}})
+	assert.NoError(t, err)
+	assert.Equal(t, notes.SortTitle, repo.query.Sort)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type TrashService struct {
+	repo      repository.Trash
+	retention time.Duration
+}
+
+// NewTrashService returns the trash service. Trashed lists and items are
+// purged once they are older than retention; zero keeps them forever.
+func NewTrashService(repo repository.Trash, retention time.Duration) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		retention: retention,
+	}
+}
+
+func (s *TrashService) GetAll(userId int) ([]notes.TrashEntry, error) {
+	entries, err := s.repo.GetAll(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.retention > 0 {
+		for i := range entries {
+			purgeAt := entries[i].DeletedAt.Add(s.retention)
+			entries[i].PurgeAt = &purgeAt
+		}
+	}
+
+	return entries, nil
+}
+
+// purge is the body of the trash purge job.
+func (s *TrashService) purge() error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	purged, err := s.repo.Purge(time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		logrus.Infof("purged %d trashed lists and items", purged)
+	}
+
+	return nil
+}
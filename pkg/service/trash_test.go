@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Liopun/notes-app"
+	"github.com/Liopun/notes-app/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTrashRepo struct {
+	repository.Trash
+	entries []notes.TrashEntry
+	purged  []time.Time
+}
+
+func (r *fakeTrashRepo) GetAll(userId int) ([]notes.TrashEntry, error) {
+	return r.entries, nil
+}
+
+func (r *fakeTrashRepo) Purge(before time.Time) (int64, error) {
+	r.purged = append(r.purged, before)
+	return 0, nil
+}
+
+func TestTrashService_GetAll(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeTrashRepo{entries: []notes.TrashEntry{{Type: notes.TrashItem, Id: 1, DeletedAt: deletedAt}}}
+
+	entries, err := NewTrashService(repo, 24*time.Hour).GetAll(1)
+	assert.NoError(t, err)
+	assert.Equal(t, deletedAt.Add(24*time.Hour), *entries[0].PurgeAt)
+
+	repo.entries[0].PurgeAt = nil
+	entries, err = NewTrashService(repo, 0).GetAll(1)
+	assert.NoError(t, err)
+	assert.Nil(t, entries[0].PurgeAt)
+}
+
+func TestTrashService_purge(t *testing.T) {
+	repo := &fakeTrashRepo{}
+
+	assert.NoError(t, NewTrashService(repo, 0).purge())
+	assert.Empty(t, repo.purged, "zero retention keeps the trash forever")
+
+	assert.NoError(t, NewTrashService(repo, time.Hour).purge())
+	assert.Len(t, repo.purged, 1)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), repo.purged[0], time.Minute)
+}
@@ -0,0 +1,336 @@
+package notes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceDay is a BYDAY entry. N picks the Nth weekday of the month,
+// counting from the end when negative; zero means every such weekday.
+type RecurrenceDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func (d RecurrenceDay) String() string {
+	code := strings.ToUpper(d.Weekday.String()[:2])
+	if d.N != 0 {
+		return strconv.Itoa(d.N) + code
+	}
+
+	return code
+}
+
+// Recurrence is the subset of an iCalendar RRULE that items can repeat by:
+// FREQ of DAILY, WEEKLY or MONTHLY, INTERVAL, BYDAY and one of COUNT or
+// UNTIL, e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10". Ordinal days
+// such as "1MO" or "-1FR" are only allowed in monthly rules.
+type Recurrence struct {
+	Freq     string
+	Interval int
+	ByDay    []RecurrenceDay
+	Count    int
+	Until    *time.Time
+}
+
+// ParseRecurrence parses an RRULE, with or without the "RRULE:" prefix.
+func ParseRecurrence(s string) (Recurrence, error) {
+	rule := Recurrence{Interval: 1}
+	seen := make(map[string]bool)
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		if !ok || value == "" {
+			return Recurrence{}, fmt.Errorf("invalid recurrence part %q", part)
+		}
+
+		if seen[key] {
+			return Recurrence{}, fmt.Errorf("recurrence repeats %s", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+			if rule.Freq != FreqDaily && rule.Freq != FreqWeekly && rule.Freq != FreqMonthly {
+				return Recurrence{}, fmt.Errorf("unsupported recurrence frequency %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		default:
+			return Recurrence{}, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+		if err != nil {
+			return Recurrence{}, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return Recurrence{}, fmt.Errorf("recurrence requires FREQ")
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return Recurrence{}, fmt.Errorf("recurrence can't have both COUNT and UNTIL")
+	}
+
+	for _, d := range rule.ByDay {
+		if d.N != 0 && rule.Freq != FreqMonthly {
+			return Recurrence{}, fmt.Errorf("BYDAY %s is only allowed with FREQ=%s", d, FreqMonthly)
+		}
+	}
+
+	return rule, nil
+}
+
+func parsePositive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", key)
+	}
+
+	return n, nil
+}
+
+func parseUntil(value string) (*time.Time, error) {
+	until, err := time.Parse(untilLayout, value)
+	if err != nil {
+		// A date alone includes the whole day.
+		day, dateErr := time.Parse(untilDateLayout, value)
+		if dateErr != nil {
+			return nil, fmt.Errorf("UNTIL must look like %s or %s", untilDateLayout, untilLayout)
+		}
+		until = day.Add(24*time.Hour - time.Second)
+	}
+
+	return &until, nil
+}
+
+func parseByDay(value string) ([]RecurrenceDay, error) {
+	var days []RecurrenceDay
+	for _, entry := range strings.Split(strings.ToUpper(value), ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", entry)
+		}
+
+		weekday, ok := weekdayCodes[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", entry)
+		}
+
+		day := RecurrenceDay{Weekday: weekday}
+		if ordinal := entry[:len(entry)-2]; ordinal != "" {
+			n, err := strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %q", entry)
+			}
+			day.N = n
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// String returns the rule in canonical form.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// maxRecurrenceSteps bounds the search for the next occurrence of rules
+// that rarely match, such as the 31st of every other month.
+const maxRecurrenceSteps = 60
+
+// Next returns the occurrence following the occurrence-th one, which falls
+// on prev; the time of day is kept. ok is false once the series has ended.
+func (r Recurrence) Next(prev time.Time, occurrence int) (next time.Time, ok bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		next, ok = r.nextDaily(prev)
+	case FreqWeekly:
+		next, ok = r.nextWeekly(prev)
+	case FreqMonthly:
+		next, ok = r.nextMonthly(prev)
+	}
+
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+func (r Recurrence) onDay(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, d := range r.ByDay {
+		if d.Weekday == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Recurrence) nextDaily(prev time.Time) (time.Time, bool) {
+	next := prev
+	for i := 0; i < maxRecurrenceSteps; i++ {
+		next = next.AddDate(0, 0, r.Interval)
+		if r.onDay(next.Weekday()) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// weekOffset is the day of the week counted from Monday, which RRULE weeks
+// start on.
+func weekOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func (r Recurrence) nextWeekly(prev time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval), true
+	}
+
+	offsets := make([]int, 0, len(r.ByDay))
+	for _, d := range r.ByDay {
+		offsets = append(offsets, weekOffset(d.Weekday))
+	}
+	sort.Ints(offsets)
+
+	current := weekOffset(prev.Weekday())
+	weekStart := prev.AddDate(0, 0, -current)
+	for _, offset := range offsets {
+		if offset > current {
+			return weekStart.AddDate(0, 0, offset), true
+		}
+	}
+
+	return weekStart.AddDate(0, 0, 7*r.Interval+offsets[0]), true
+}
+
+func (r Recurrence) nextMonthly(prev time.Time) (time.Time, bool) {
+	for i := 0; i < maxRecurrenceSteps; i++ {
+		month := time.Date(prev.Year(), prev.Month()+time.Month(i*r.Interval), 1, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+
+		var days []int
+		if len(r.ByDay) == 0 {
+			days = []int{prev.Day()}
+		} else {
+			days = r.monthDays(month)
+		}
+
+		for _, day := range days {
+			if day > daysIn(month) {
+				continue
+			}
+
+			next := month.AddDate(0, 0, day-1)
+			if next.After(prev) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// monthDays returns the days of month matched by BYDAY, in order.
+func (r Recurrence) monthDays(month time.Time) []int {
+	last := daysIn(month)
+	first := month.Weekday()
+
+	matched := make(map[int]bool)
+	for _, d := range r.ByDay {
+		firstDay := 1 + (int(d.Weekday)-int(first)+7)%7
+		switch {
+		case d.N > 0:
+			matched[firstDay+7*(d.N-1)] = true
+		case d.N < 0:
+			lastDay := firstDay + 7*((last-firstDay)/7)
+			matched[lastDay+7*(d.N+1)] = true
+		default:
+			for day := firstDay; day <= last; day += 7 {
+				matched[day] = true
+			}
+		}
+	}
+
+	days := make([]int, 0, len(matched))
+	for day := range matched {
+		if day >= 1 && day <= last {
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+
+	return days
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,64 @@
+package notes
+
+import "time"
+
+// Channels reminders are delivered through.
+const (
+	ChannelInbox   = "inbox"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Delivery states. Pending deliveries are retried until they are sent or
+// run out of attempts. Deliveries whose item was trashed or marked done
+// before they went out are cancelled.
+const (
+	DeliveryPending   = "pending"
+	DeliverySent      = "sent"
+	DeliveryFailed    = "failed"
+	DeliveryCancelled = "cancelled"
+)
+
+// ReminderDelivery is one reminder of an item to one member of its list
+// through one channel.
+type ReminderDelivery struct {
+	Id            int        `json:"id" db:"id"`
+	ItemId        int        `json:"item_id" db:"item_id"`
+	Channel       string     `json:"channel" db:"channel"`
+	RemindAt      time.Time  `json:"remind_at" db:"remind_at"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	LastError     *string    `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" db:"delivered_at"`
+}
+
+// Reminder is a delivery claimed for sending, with what a notifier needs
+// to know about the item and the recipient. It is also the payload of
+// webhook notifications.
+type Reminder struct {
+	DeliveryId  int        `json:"delivery_id" db:"id"`
+	Channel     string     `json:"channel" db:"channel"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	ItemId      int        `json:"item_id" db:"item_id"`
+	ListId      int        `json:"list_id" db:"list_id"`
+	Title       string     `json:"title" db:"title"`
+	Description string     `json:"description" db:"description"`
+	DueAt       *time.Time `json:"due_at" db:"due_at"`
+	RemindAt    time.Time  `json:"remind_at" db:"remind_at"`
+	Username    string     `json:"username" db:"username"`
+	Name        string     `json:"name" db:"name"`
+	Email       *string    `json:"-" db:"email"`
+	UserId      int        `json:"-" db:"user_id"`
+}
+
+// InboxMessage is a notification shown inside the app.
+type InboxMessage struct {
+	Id        int        `json:"id" db:"id"`
+	UserId    int        `json:"-" db:"user_id"`
+	ItemId    *int       `json:"item_id" db:"item_id"`
+	Title     string     `json:"title" db:"title"`
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+}
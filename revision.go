@@ -0,0 +1,37 @@
+package notes
+
+import "time"
+
+// ItemRevision is the state of an item after one of its writes. Revisions
+// are numbered by the item version they produced and never change.
+type ItemRevision struct {
+	ItemId      int       `json:"item_id" db:"item_id"`
+	Version     int       `json:"version" db:"version"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description,omitempty" db:"description"`
+	Archived    bool      `json:"archived" db:"archived"`
+	Author      *string   `json:"author" db:"author"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine is one line of a line-level diff.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff compares two revisions of an item.
+type RevisionDiff struct {
+	From         int        `json:"from"`
+	To           int        `json:"to"`
+	Title        []DiffLine `json:"title"`
+	Description  []DiffLine `json:"description"`
+	ArchivedFrom bool       `json:"archived_from"`
+	ArchivedTo   bool       `json:"archived_to"`
+}
@@ -1,9 +1,9 @@
-DROP TABLE users;
-
-DROP TABLE users_lists
-
 DROP TABLE lists_items;
 
 DROP TABLE notes_items;
 
-DROP TABLE notes_lists;
\ No newline at end of file
+DROP TABLE users_lists;
+
+DROP TABLE notes_lists;
+
+DROP TABLE users;
@@ -0,0 +1,17 @@
+CREATE TABLE refresh_tokens (
+    id         SERIAL NOT NULL UNIQUE,
+    user_id    int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    family_id  VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+CREATE TABLE revoked_tokens (
+    jti        VARCHAR(64) NOT NULL PRIMARY KEY,
+    expires_at timestamptz NOT NULL
+);
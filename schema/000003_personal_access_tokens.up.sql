@@ -0,0 +1,13 @@
+CREATE TABLE personal_access_tokens (
+    id           SERIAL NOT NULL UNIQUE,
+    user_id      int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    token_hash   VARCHAR(64) NOT NULL UNIQUE,
+    scopes       TEXT NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    last_used_at timestamptz,
+    expires_at   timestamptz,
+    revoked_at   timestamptz
+);
+
+CREATE INDEX personal_access_tokens_user_id_idx ON personal_access_tokens (user_id);
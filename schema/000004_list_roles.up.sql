@@ -0,0 +1,5 @@
+ALTER TABLE users_lists ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'owner';
+
+ALTER TABLE users_lists ADD CONSTRAINT users_lists_role_check CHECK (role IN ('owner', 'editor', 'viewer'));
+
+ALTER TABLE users_lists ADD CONSTRAINT users_lists_user_id_list_id_key UNIQUE (user_id, list_id);
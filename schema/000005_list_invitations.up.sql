@@ -0,0 +1,14 @@
+CREATE TABLE list_invitations (
+    id         SERIAL NOT NULL UNIQUE,
+    list_id    int REFERENCES notes_lists(id) ON DELETE CASCADE NOT NULL,
+    inviter_id int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    invitee_id int REFERENCES users(id) ON DELETE CASCADE,
+    role       VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    status     VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'revoked', 'expired')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX list_invitations_invitee_id_idx ON list_invitations (invitee_id) WHERE status = 'pending';
+
+CREATE INDEX list_invitations_expires_at_idx ON list_invitations (expires_at) WHERE status = 'pending';
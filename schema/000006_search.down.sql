@@ -0,0 +1,7 @@
+DROP INDEX notes_items_search_vector_idx;
+
+ALTER TABLE notes_items DROP COLUMN search_vector;
+
+DROP INDEX notes_lists_search_vector_idx;
+
+ALTER TABLE notes_lists DROP COLUMN search_vector;
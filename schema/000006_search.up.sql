@@ -0,0 +1,13 @@
+ALTER TABLE notes_lists ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX notes_lists_search_vector_idx ON notes_lists USING GIN (search_vector);
+
+ALTER TABLE notes_items ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX notes_items_search_vector_idx ON notes_items USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE notes_items DROP COLUMN created_at, DROP COLUMN updated_at;
+
+ALTER TABLE notes_lists DROP COLUMN created_at, DROP COLUMN updated_at;
@@ -0,0 +1,11 @@
+ALTER TABLE notes_lists
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+
+ALTER TABLE notes_items
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+
+CREATE INDEX notes_items_created_at_idx ON notes_items (created_at, id);
+
+CREATE INDEX notes_items_updated_at_idx ON notes_items (updated_at, id);
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN created_at, DROP COLUMN updated_at;
@@ -0,0 +1,3 @@
+ALTER TABLE notes_items DROP COLUMN version;
+
+ALTER TABLE notes_lists DROP COLUMN version;
@@ -0,0 +1,3 @@
+ALTER TABLE notes_lists ADD COLUMN version int NOT NULL DEFAULT 1;
+
+ALTER TABLE notes_items ADD COLUMN version int NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+CREATE TABLE item_revisions (
+    id          SERIAL NOT NULL UNIQUE,
+    item_id     int REFERENCES notes_items(id) ON DELETE CASCADE NOT NULL,
+    version     int NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    archived    boolean NOT NULL,
+    author_id   int REFERENCES users(id) ON DELETE SET NULL,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (item_id, version)
+);
+
+INSERT INTO item_revisions (item_id, version, title, description, archived, created_at)
+SELECT id, version, title, description, archived, updated_at FROM notes_items;
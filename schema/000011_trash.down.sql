@@ -0,0 +1,7 @@
+DELETE FROM notes_items WHERE deleted_at IS NOT NULL;
+
+DELETE FROM notes_lists WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE notes_items DROP COLUMN deleted_at;
+
+ALTER TABLE notes_lists DROP COLUMN deleted_at;
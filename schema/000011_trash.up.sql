@@ -0,0 +1,7 @@
+ALTER TABLE notes_lists ADD COLUMN deleted_at timestamptz;
+
+ALTER TABLE notes_items ADD COLUMN deleted_at timestamptz;
+
+CREATE INDEX notes_lists_deleted_at_idx ON notes_lists (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX notes_items_deleted_at_idx ON notes_items (deleted_at) WHERE deleted_at IS NOT NULL;
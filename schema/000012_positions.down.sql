@@ -0,0 +1,3 @@
+ALTER TABLE users_lists DROP COLUMN position;
+
+ALTER TABLE notes_items DROP COLUMN position;
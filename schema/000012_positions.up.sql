@@ -0,0 +1,27 @@
+ALTER TABLE notes_items ADD COLUMN position VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+
+ALTER TABLE users_lists ADD COLUMN position VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+
+-- Existing rows keep their creation order, spaced as zero-padded numbers
+-- so that new positions fit in between.
+UPDATE notes_items ti SET position = lpad(ranked.n::text, 10, '0')
+FROM (
+    SELECT ti.id, row_number() OVER (PARTITION BY li.list_id ORDER BY ti.created_at, ti.id) AS n
+    FROM notes_items ti INNER JOIN lists_items li on li.item_id = ti.id
+) ranked
+WHERE ranked.id = ti.id;
+
+UPDATE users_lists ul SET position = lpad(ranked.n::text, 10, '0')
+FROM (
+    SELECT ul.id, row_number() OVER (PARTITION BY ul.user_id ORDER BY tl.created_at, tl.id) AS n
+    FROM users_lists ul INNER JOIN notes_lists tl on tl.id = ul.list_id
+) ranked
+WHERE ranked.id = ul.id;
+
+ALTER TABLE notes_items ALTER COLUMN position DROP DEFAULT;
+
+ALTER TABLE users_lists ALTER COLUMN position DROP DEFAULT;
+
+CREATE INDEX notes_items_position_idx ON notes_items (position, id);
+
+CREATE INDEX users_lists_position_idx ON users_lists (user_id, position, list_id);
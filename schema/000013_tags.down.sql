@@ -0,0 +1,3 @@
+DROP TABLE item_tags;
+
+DROP TABLE tags;
@@ -0,0 +1,17 @@
+CREATE TABLE tags (
+    id         SERIAL NOT NULL UNIQUE,
+    user_id    int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    name       VARCHAR(64) NOT NULL,
+    color      VARCHAR(7) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX tags_user_name_idx ON tags (user_id, lower(name));
+
+CREATE TABLE item_tags (
+    item_id int REFERENCES notes_items(id) ON DELETE CASCADE NOT NULL,
+    tag_id  int REFERENCES tags(id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (item_id, tag_id)
+);
+
+CREATE INDEX item_tags_tag_id_idx ON item_tags (tag_id, item_id);
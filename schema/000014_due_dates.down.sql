@@ -0,0 +1,7 @@
+ALTER TABLE notes_items DROP COLUMN done_at;
+
+ALTER TABLE notes_items DROP COLUMN done;
+
+ALTER TABLE notes_items DROP COLUMN priority;
+
+ALTER TABLE notes_items DROP COLUMN due_at;
@@ -0,0 +1,9 @@
+ALTER TABLE notes_items ADD COLUMN due_at timestamptz;
+
+ALTER TABLE notes_items ADD COLUMN priority smallint NOT NULL DEFAULT 0;
+
+ALTER TABLE notes_items ADD COLUMN done boolean NOT NULL DEFAULT false;
+
+ALTER TABLE notes_items ADD COLUMN done_at timestamptz;
+
+CREATE INDEX notes_items_due_at_idx ON notes_items (due_at) WHERE due_at IS NOT NULL AND NOT done AND deleted_at IS NULL;
@@ -0,0 +1,9 @@
+DROP TABLE inbox_messages;
+
+DROP TABLE reminder_deliveries;
+
+ALTER TABLE notes_items DROP COLUMN reminded_at;
+
+ALTER TABLE notes_items DROP COLUMN remind_at;
+
+ALTER TABLE users DROP COLUMN email;
@@ -0,0 +1,38 @@
+ALTER TABLE users ADD COLUMN email VARCHAR(255);
+
+ALTER TABLE notes_items ADD COLUMN remind_at timestamptz;
+
+ALTER TABLE notes_items ADD COLUMN reminded_at timestamptz;
+
+CREATE INDEX notes_items_remind_at_idx ON notes_items (remind_at) WHERE remind_at IS NOT NULL AND reminded_at IS NULL;
+
+CREATE TABLE reminder_deliveries (
+    id              SERIAL NOT NULL UNIQUE,
+    item_id         int REFERENCES notes_items(id) ON DELETE CASCADE NOT NULL,
+    user_id         int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    channel         VARCHAR(32) NOT NULL,
+    remind_at       timestamptz NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        int NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    delivered_at    timestamptz
+);
+
+CREATE INDEX reminder_deliveries_pending_idx ON reminder_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX reminder_deliveries_item_idx ON reminder_deliveries (item_id, user_id);
+
+CREATE TABLE inbox_messages (
+    id          SERIAL NOT NULL UNIQUE,
+    user_id     int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    item_id     int REFERENCES notes_items(id) ON DELETE SET NULL,
+    delivery_id int UNIQUE REFERENCES reminder_deliveries(id) ON DELETE SET NULL,
+    title       VARCHAR(255) NOT NULL,
+    body        TEXT NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    read_at     timestamptz
+);
+
+CREATE INDEX inbox_messages_user_idx ON inbox_messages (user_id, created_at);
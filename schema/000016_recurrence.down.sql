@@ -0,0 +1,3 @@
+ALTER TABLE notes_items DROP COLUMN occurrence;
+
+ALTER TABLE notes_items DROP COLUMN recurrence;
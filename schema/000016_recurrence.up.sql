@@ -0,0 +1,3 @@
+ALTER TABLE notes_items ADD COLUMN recurrence varchar(255);
+
+ALTER TABLE notes_items ADD COLUMN occurrence int NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DROP TABLE checklist_entries;
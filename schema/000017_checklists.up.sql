@@ -0,0 +1,11 @@
+CREATE TABLE checklist_entries (
+    id         SERIAL NOT NULL UNIQUE,
+    item_id    int REFERENCES notes_items(id) ON DELETE CASCADE NOT NULL,
+    text       VARCHAR(500) NOT NULL,
+    checked    boolean NOT NULL DEFAULT false,
+    position   VARCHAR(255) COLLATE "C" NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX checklist_entries_item_id_idx ON checklist_entries (item_id, position, id);
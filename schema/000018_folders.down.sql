@@ -0,0 +1,3 @@
+ALTER TABLE users_lists DROP COLUMN folder_id;
+
+DROP TABLE folders;
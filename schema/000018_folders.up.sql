@@ -0,0 +1,11 @@
+CREATE TABLE folders (
+    id         SERIAL NOT NULL UNIQUE,
+    user_id    int REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    parent_id  int REFERENCES folders(id) ON DELETE CASCADE,
+    name       VARCHAR(255) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX folders_user_id_idx ON folders (user_id, parent_id);
+
+ALTER TABLE users_lists ADD COLUMN folder_id int REFERENCES folders(id) ON DELETE SET NULL;
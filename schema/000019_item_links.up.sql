@@ -0,0 +1,8 @@
+CREATE TABLE item_links (
+    source_id int REFERENCES notes_items(id) ON DELETE CASCADE NOT NULL,
+    ref       VARCHAR(255) NOT NULL,
+    target_id int REFERENCES notes_items(id) ON DELETE SET NULL,
+    PRIMARY KEY (source_id, ref)
+);
+
+CREATE INDEX item_links_target_id_idx ON item_links (target_id);
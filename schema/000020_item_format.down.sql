@@ -0,0 +1 @@
+ALTER TABLE notes_items DROP COLUMN format;
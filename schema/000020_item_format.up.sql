@@ -0,0 +1 @@
+ALTER TABLE notes_items ADD COLUMN format varchar(16) NOT NULL DEFAULT 'plain';
@@ -0,0 +1,8 @@
+// Package schema embeds the versioned SQL migrations so the binary can
+// apply them without the files being present on disk.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var Migrations embed.FS
@@ -0,0 +1,72 @@
+package notes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// PersonalTokenPrefix marks personal access tokens so they can be told apart
+// from JWTs without parsing.
+const PersonalTokenPrefix = "nap_"
+
+const (
+	ScopeListsRead  = "lists:read"
+	ScopeListsWrite = "lists:write"
+	ScopeItemsRead  = "items:read"
+	ScopeItemsWrite = "items:write"
+)
+
+var knownScopes = map[string]bool{
+	ScopeListsRead:  true,
+	ScopeListsWrite: true,
+	ScopeItemsRead:  true,
+	ScopeItemsWrite: true,
+}
+
+// Scopes is a set of permissions granted to a personal access token. It is
+// stored as a space-separated string, like an OAuth scope parameter.
+type Scopes []string
+
+func (s Scopes) Has(scope string) bool {
+	for _, sc := range s {
+		if sc == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Scopes) Validate() error {
+	if len(s) == 0 {
+		return fmt.Errorf(validationError, "scopes")
+	}
+
+	for _, sc := range s {
+		if !knownScopes[sc] {
+			return fmt.Errorf("unknown scope %q", sc)
+		}
+	}
+
+	return nil
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	return strings.Join(s, " "), nil
+}
+
+func (s *Scopes) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		*s = strings.Fields(v)
+	case []byte:
+		*s = strings.Fields(string(v))
+	case nil:
+		*s = nil
+	default:
+		return fmt.Errorf("cannot scan %T into Scopes", src)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package notes
+
+import "fmt"
+
+const (
+	SearchResultList = "list"
+	SearchResultItem = "item"
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchResult is a list or item matching a search query. Snippet is an
+// HTML-escaped excerpt of the description with the matched terms wrapped in
+// <mark> tags.
+type SearchResult struct {
+	Type    string  `json:"type" db:"type"`
+	Id      int     `json:"id" db:"id"`
+	ListId  int     `json:"list_id" db:"list_id"`
+	Title   string  `json:"title" db:"title"`
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"rank" db:"rank"`
+}
+
+// SearchInput is a web-search style query: quoted phrases, "or" and a
+// leading "-" to exclude a term are understood. A Limit of 0 means
+// DefaultSearchLimit.
+type SearchInput struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+func (inp SearchInput) Validate() error {
+	if inp.Query == "" {
+		return fmt.Errorf(validationError, "search input")
+	}
+
+	if inp.Limit < 0 || inp.Limit > MaxSearchLimit {
+		return fmt.Errorf("limit must be between 1 and %d, or 0 for the default of %d", MaxSearchLimit, DefaultSearchLimit)
+	}
+
+	return nil
+}
@@ -3,25 +3,47 @@ package notes
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type Server struct {
+	mu         sync.Mutex
 	httpServer *http.Server
+	shutdown   bool
 }
 
 func (s *Server) Run(port string, handler http.Handler) error {
-	s.httpServer = &http.Server{
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return http.ErrServerClosed
+	}
+
+	srv := &http.Server{
 		Addr:           ":" + port,
 		Handler:        handler,
 		MaxHeaderBytes: 1 << 20, // 1Mb
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 	}
+	s.httpServer = srv
+	s.mu.Unlock()
 
-	return s.httpServer.ListenAndServe()
+	return srv.ListenAndServe()
 }
 
+// Shutdown gracefully stops the server. Run is usually started in its own
+// goroutine, so Shutdown may come first; Run then returns
+// http.ErrServerClosed without serving.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdown = true
+	if s.httpServer == nil {
+		return nil
+	}
+
 	return s.httpServer.Shutdown(ctx)
 }
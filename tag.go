@@ -0,0 +1,100 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultTagColor = "#808080"
+	MaxTagName      = 64
+
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Tag is a label a user puts on items. Tags are personal: every user has
+// their own, and tagging a shared item doesn't show up for other members.
+// Names are unique per user, ignoring case.
+type Tag struct {
+	Id    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"name" binding:"required"`
+	Color string `json:"color" db:"color"`
+}
+
+func (t Tag) Validate() error {
+	if err := validateTagName(t.Name); err != nil {
+		return err
+	}
+
+	if t.Color != "" && !tagColorPattern.MatchString(t.Color) {
+		return fmt.Errorf("color must look like #rrggbb")
+	}
+
+	return nil
+}
+
+type UpdateTagInput struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+func (inp UpdateTagInput) Validate() error {
+	if inp.Name == nil && inp.Color == nil {
+		return fmt.Errorf(validationError, "update tag input")
+	}
+
+	if inp.Name != nil {
+		if err := validateTagName(*inp.Name); err != nil {
+			return err
+		}
+	}
+
+	if inp.Color != nil && !tagColorPattern.MatchString(*inp.Color) {
+		return fmt.Errorf("color must look like #rrggbb")
+	}
+
+	return nil
+}
+
+func validateTagName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf(validationError, "tag input")
+	}
+
+	if len(name) > MaxTagName {
+		return fmt.Errorf("tag name must be at most %d bytes", MaxTagName)
+	}
+
+	return nil
+}
+
+// MergeTagInput names the tag that takes over the items of the merged one.
+type MergeTagInput struct {
+	IntoId int `json:"into_id" binding:"required"`
+}
+
+// TaggedItemsQuery pages through the items carrying all (the default) or
+// any of the given tags, across every list the user can read.
+type TaggedItemsQuery struct {
+	PageQuery
+	TagIds []int  `form:"tag"`
+	Match  string `form:"match"`
+}
+
+func (q TaggedItemsQuery) Validate() error {
+	if len(q.TagIds) == 0 {
+		return fmt.Errorf(validationError, "tagged items query")
+	}
+
+	switch q.Match {
+	case "", TagMatchAll, TagMatchAny:
+	default:
+		return fmt.Errorf("unknown match %q", q.Match)
+	}
+
+	return q.PageQuery.Validate()
+}
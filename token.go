@@ -0,0 +1,73 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tokens is the pair handed out on sign-in and on every refresh.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken is the server-side record of an issued refresh token. Only
+// the SHA-256 of the token is stored. Tokens rotated from one sign-in share
+// a FamilyId so a replayed token can revoke the whole chain.
+type RefreshToken struct {
+	Id        int        `db:"id"`
+	UserId    int        `db:"user_id"`
+	FamilyId  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+// JSONWebKey is the RFC 7517 representation of a public verification key.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PersonalAccessToken is a long-lived, scoped credential for scripts. The
+// token itself is only returned once, when it is created.
+type PersonalAccessToken struct {
+	Id         int        `json:"id" db:"id"`
+	UserId     int        `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Scopes     Scopes     `json:"scopes" db:"scopes"`
+	Token      string     `json:"token,omitempty" db:"-"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+}
+
+type CreateTokenInput struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    Scopes     `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (inp CreateTokenInput) Validate() error {
+	if inp.Name == "" {
+		return fmt.Errorf(validationError, "create token input")
+	}
+
+	if inp.ExpiresAt != nil && !inp.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	return inp.Scopes.Validate()
+}
@@ -0,0 +1,20 @@
+package notes
+
+import "time"
+
+const (
+	TrashList = "list"
+	TrashItem = "item"
+)
+
+// TrashEntry is a deleted list or item that can still be restored. The
+// items of a trashed list are not listed separately; they come back with
+// the list.
+type TrashEntry struct {
+	Type      string     `json:"type" db:"type"`
+	Id        int        `json:"id" db:"id"`
+	ListId    int        `json:"list_id" db:"list_id"`
+	Title     string     `json:"title" db:"title"`
+	DeletedAt time.Time  `json:"deleted_at" db:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty" db:"-"`
+}
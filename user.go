@@ -1,8 +1,13 @@
 package notes
 
+import "time"
+
 type User struct {
-	Id       int    `json:"-" db:"id"`
-	Name     string `json:"name" binding:"required"`
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Id        int       `json:"-" db:"id"`
+	Name      string    `json:"name" binding:"required"`
+	Username  string    `json:"username" binding:"required"`
+	Password  string    `json:"password" binding:"required"`
+	Email     string    `json:"email" binding:"omitempty,email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }